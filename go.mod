@@ -0,0 +1,3 @@
+module github.com/Parallel-7/FlashForgeUI-Electron
+
+go 1.22
@@ -1,15 +1,23 @@
+//go:build ignore
+
 package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
 )
 
 // Constants
@@ -22,43 +30,146 @@ var supportedExtensions = map[string]bool{
 	".jsx": true,
 }
 
+// Exit codes shared by every scanner
+const (
+	exitFindings = 1 // findings over a --fail-on-* / --max threshold
+	exitError    = 2 // invalid usage or a runtime/I/O error
+)
+
+// Output formats accepted by --format
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatMarkdown = "markdown"
+)
+
+// Issues reported for files that fail the check
+const (
+	issueMissing = "missing"
+	issueEmpty   = "empty description"
+)
+
+// Issue kinds accepted by --severity; see issueKind
+const (
+	kindMissing     = "missing"
+	kindMisplaced   = "misplaced"
+	kindEmpty       = "empty"
+	kindMissingTags = "missing-tags"
+)
+
 // MissingFile structure to hold report data
 type MissingFile struct {
-	File      string
-	FirstLine string
+	File      string `json:"file"`
+	Issue     string `json:"issue"`
+	FirstLine string `json:"firstLine"`
+	// Severity is the --severity level of the issue's kind
+	Severity string `json:"severity"`
 }
 
-// collectSourceFiles walks the directory and collects matching files
-func collectSourceFiles(rootDir string) ([]string, error) {
-	var files []string
+// checkOptions controls what counts as a documented file
+// Shared read-only across workers
+type checkOptions struct {
+	linesToCheck       int
+	requireDescription bool
+	detectMisplaced    bool
+	requiredTags       []requiredTag
+	patterns           []*regexp.Regexp
+	// tagPattern locates the tag itself; the description is everything after it
+	tagPattern *regexp.Regexp
+}
 
-	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+// checkResult is one worker's verdict for one file
+type checkResult struct {
+	found     bool
+	issue     string
+	firstLine string
+	lines     int
+}
 
-		if info.IsDir() {
-			return nil
-		}
+// requiredTag is an extra JSDoc tag demanded by --require-tags
+type requiredTag struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// defaultTag is the documentation tag checked unless --tag overrides it
+const defaultTag = "fileoverview"
+
+// docTag is the tag label ("@fileoverview" or the --tag override) used in output
+var docTag = "@" + defaultTag
 
-		ext := filepath.Ext(info.Name())
-		if supportedExtensions[ext] {
-			files = append(files, path)
+// Strips comment decoration (leading `*`, `//`, closing `*/`) from a line
+var commentDecorationPattern = regexp.MustCompile(`^\s*(?://+|\*+(?:/)?)?\s?`)
+
+// stringSlice handles comma-separated flags or multiple flag occurrences
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	parts := strings.Split(value, ",")
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			*s = append(*s, trimmed)
 		}
-		return nil
-	})
+	}
+	return nil
+}
+
+// Matches re-export/import statements such as `export * from './a'` or
+// `export { b, type C } from './b'` (multi-line braces included)
+var reexportPattern = regexp.MustCompile(`\b(?:export|import)\b[^;'"]*?\bfrom\s*['"][^'"]+['"]\s*;?`)
+
+// Strips block and line comments before barrel detection
+var commentPattern = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
 
-	return files, err
+// DirCoverage holds documentation coverage for one top-level directory
+type DirCoverage struct {
+	Dir        string  `json:"dir"`
+	Documented int     `json:"documented"`
+	Total      int     `json:"total"`
+	Percent    float64 `json:"percent"`
 }
 
-// buildPatternList compiles the regex patterns
-func buildPatternList() []*regexp.Regexp {
-	// Go uses (?i) for case insensitivity
+// DirMissing counts reported files per top-level directory
+type DirMissing struct {
+	Dir     string `json:"dir"`
+	Missing int    `json:"missing"`
+}
+
+// reportSummary carries the totals every output format prints. Coverage is
+// always computed over all files, even when --baseline hides known gaps.
+type reportSummary struct {
+	totalFiles   int
+	documented   int
+	baselined    int
+	directories  []DirCoverage
+	missingByDir []DirMissing
+}
+
+// jsonReport is the document written by --format=json
+type jsonReport struct {
+	TotalFiles      int           `json:"totalFiles"`
+	DocumentedCount int           `json:"documentedCount"`
+	MissingCount    int           `json:"missingCount"`
+	BaselinedCount  int           `json:"baselinedCount,omitempty"`
+	CoveragePercent float64       `json:"coveragePercent"`
+	Missing         []MissingFile `json:"missing"`
+	Directories     []DirCoverage `json:"directories,omitempty"`
+	MissingByDir    []DirMissing  `json:"missingByDir,omitempty"`
+}
+
+// buildPatternList compiles the regex patterns for the given tag name
+func buildPatternList(tag string) []*regexp.Regexp {
+	quoted := regexp.QuoteMeta(tag)
+	// Go uses (?i) for case insensitivity; \b keeps `@file` from matching `@fileoverview`
 	patterns := []string{
-		`(?i)@fileoverview`,
-		`(?i)@\s*fileoverview`,
-		`(?i)\*\s*@fileoverview`,
-		`(?i)//\s*@fileoverview`,
+		`(?i)@` + quoted + `\b`,
+		`(?i)@\s*` + quoted + `\b`,
+		`(?i)\*\s*@` + quoted + `\b`,
+		`(?i)//\s*@` + quoted + `\b`,
 	}
 
 	var compiled []*regexp.Regexp
@@ -68,11 +179,14 @@ func buildPatternList() []*regexp.Regexp {
 	return compiled
 }
 
-// hasFileOverview checks the top N lines of a file for the patterns
-func hasFileOverview(filePath string, linesToCheck int) (bool, string, error) {
+// hasFileOverview checks the top N lines of a file for the patterns. When the
+// file fails the check the returned issue says why.
+func hasFileOverview(filePath string, opts checkOptions) (bool, string, string, int, error) {
+	linesToCheck := opts.linesToCheck
+
 	file, err := os.Open(filePath)
 	if err != nil {
-		return false, "", err
+		return false, "", "", 0, err
 	}
 	defer file.Close()
 
@@ -90,7 +204,7 @@ func hasFileOverview(filePath string, linesToCheck int) (bool, string, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return false, "", err
+		return false, "", "", 0, err
 	}
 
 	firstLine := "(empty file)"
@@ -99,17 +213,112 @@ func hasFileOverview(filePath string, linesToCheck int) (bool, string, error) {
 	}
 
 	snippet := strings.Join(lines, "\n")
-	patterns := buildPatternList()
-	found := false
+	found := matchesAny(snippet, opts.patterns)
+
+	if !found {
+		if opts.detectMisplaced {
+			// Keep reading past the window: a tag further down is a placement
+			// problem (e.g. a long license header), not missing documentation
+			lineNum := lineCount
+			for scanner.Scan() {
+				lineNum++
+				if matchesAny(scanner.Text(), opts.patterns) {
+					return false, fmt.Sprintf("misplaced (line %d)", lineNum), firstLine, lineNum, nil
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				return false, "", "", 0, err
+			}
+			lineCount = lineNum
+		}
+		return false, issueMissing, firstLine, lineCount, nil
+	}
+	if opts.requireDescription && overviewDescription(snippet, opts.tagPattern) == "" {
+		return false, issueEmpty, firstLine, lineCount, nil
+	}
+
+	var missingTags []string
+	for _, tag := range opts.requiredTags {
+		if !tag.pattern.MatchString(snippet) {
+			missingTags = append(missingTags, "@"+tag.name)
+		}
+	}
+	if len(missingTags) > 0 {
+		return false, "missing " + strings.Join(missingTags, ", "), firstLine, lineCount, nil
+	}
+
+	return true, "", firstLine, lineCount, nil
+}
 
+// issueKind returns the --severity kind of an issue reported by hasFileOverview
+func issueKind(issue string) string {
+	switch {
+	case issue == issueMissing:
+		return kindMissing
+	case issue == issueEmpty:
+		return kindEmpty
+	case strings.HasPrefix(issue, "misplaced"):
+		return kindMisplaced
+	default:
+		return kindMissingTags
+	}
+}
+
+func matchesAny(text string, patterns []*regexp.Regexp) bool {
 	for _, pattern := range patterns {
-		if pattern.MatchString(snippet) {
-			found = true
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// overviewDescription returns the text following the tag, mirroring
+// extract-fileoverview's extractOverviewBlock: it stops at the end of the
+// comment or at the next JSDoc tag and drops comment decoration
+func overviewDescription(snippet string, tagPattern *regexp.Regexp) string {
+	loc := tagPattern.FindStringIndex(snippet)
+	if loc == nil {
+		return ""
+	}
+
+	rest := snippet[loc[1]:]
+	lineComment := strings.HasSuffix(strings.TrimRight(snippet[:loc[0]], " \t*"), "//")
+	if !lineComment {
+		if end := strings.Index(rest, "*/"); end != -1 {
+			rest = rest[:end]
+		}
+	}
+
+	var parts []string
+	for i, line := range strings.Split(rest, "\n") {
+		if i > 0 {
+			// A line comment overview ends at the first non-`//` line
+			if lineComment && !strings.HasPrefix(strings.TrimSpace(line), "//") {
+				break
+			}
+			line = commentDecorationPattern.ReplaceAllString(line, "")
+		}
+		trimmed := strings.TrimSpace(line)
+		if i > 0 && strings.HasPrefix(trimmed, "@") {
 			break
 		}
+		if trimmed != "" {
+			parts = append(parts, trimmed)
+		}
 	}
 
-	return found, firstLine, nil
+	return strings.Join(parts, " ")
+}
+
+// checkFiles runs hasFileOverview over files with a bounded worker pool and
+// returns the results indexed like files. A file that cannot be read fails
+// the whole check; the first such error in file order is returned.
+func checkFiles(files []string, opts checkOptions, workerCount int) ([]checkResult, error) {
+	return scan.Parallel(files, workerCount, func(file string) (checkResult, error) {
+		found, issue, firstLine, lines, err := hasFileOverview(file, opts)
+		return checkResult{found: found, issue: issue, firstLine: firstLine, lines: lines}, err
+	})
 }
 
 func main() {
@@ -117,7 +326,38 @@ func main() {
 
 	// Define flags using the standard "flag" package
 	linesPtr := flag.Int("lines", defaultCheckLines, "Number of lines to check for @fileoverview")
+	tagPtr := flag.String("tag", defaultTag, "Documentation tag to require, without the @ (e.g. file or overview)")
 	debugPtr := flag.Bool("debug", false, "Enable debug output")
+	var exclude stringSlice
+	flag.Var(&exclude, "exclude", "Skip files matching these globs, e.g. **/*.spec.ts; a bare name such as fixtures skips directories with that name, in addition to node_modules, dist, and the other defaults (repeatable or comma-separated)")
+	useGitignorePtr := flag.Bool("use-gitignore", false, "Skip files ignored by git (.gitignore, .git/info/exclude, global excludes)")
+	severityPtr := flag.String("severity", "", "Severity per issue kind as kind=error|warning|info, e.g. missing=error,empty=info (kinds: missing, misplaced, empty, missing-tags; default warning)")
+	failOnPtr := flag.String("fail-on", "", "Exit with code 1 when more than N files (default 0) have an issue at LEVEL severity or above, as LEVEL[:N]")
+	failOnMissingPtr := flag.Bool("fail-on-missing", false, "Exit with code 1 when more than --max-missing files have @fileoverview issues of any kind (missing, misplaced, empty, missing-tags)")
+	maxMissingPtr := flag.Int("max-missing", 0, "Number of files with issues tolerated by --fail-on-missing")
+	flag.BoolVar(failOnMissingPtr, "fail-on-match", false, "Alias for --fail-on-missing")
+	flag.IntVar(maxMissingPtr, "max", 0, "Alias for --max-missing")
+	formatPtr := flag.String("format", formatText, "Output format: text, json, or markdown")
+	colorPtr := flag.String("color", scan.ColorAuto, "Color the text report: auto (terminal without NO_COLOR), always, or never")
+	var ignoreGlobs stringSlice
+	flag.Var(&ignoreGlobs, "ignore-glob", "Glob of files to ignore in addition to *.d.ts and index barrels (repeatable or comma-separated, e.g. **/*.generated.ts)")
+	detectMisplacedPtr := flag.Bool("detect-misplaced", false, "Scan the rest of a file that fails the check and report a lower @fileoverview as misplaced")
+	requireDescriptionPtr := flag.Bool("require-description", false, "Also flag files whose @fileoverview has no description text")
+	var requireTags stringSlice
+	flag.Var(&requireTags, "require-tags", "Additional tags every overview header must contain, e.g. module,author")
+	baselinePtr := flag.String("baseline", "", "Baseline JSON of known header issues keyed by file and issue kind (e.g. fileoverview-baseline.json); only new ones are reported")
+	updateBaselinePtr := flag.Bool("update-baseline", false, "Rewrite the --baseline file with the current missing files and exit")
+	changedPtr := flag.Bool("changed", false, "Only check files changed on this branch (git diff --name-only <base>...HEAD)")
+	changedBasePtr := flag.String("changed-base", "origin/main", "Base ref used by --changed")
+	stagedPtr := flag.Bool("staged", false, "Only check files staged for commit (git diff --cached --name-only), e.g. from a pre-commit hook")
+	workersPtr := flag.Int("workers", runtime.NumCPU(), "Number of worker goroutines checking files in parallel")
+	missingByDirPtr := flag.Bool("missing-by-dir", false, "Summarize missing files per top-level directory, worst first")
+	byDirPtr := flag.Bool("coverage-by-dir", false, "Break the coverage percentage down per top-level directory")
+	fixPtr := flag.Bool("fix", false, "Prepend a TODO @fileoverview stub to every file missing one")
+	statsPtr := flag.Bool("stats", false, "Print a footer with files checked, lines read, issues found, and elapsed time")
+	quietPtr := flag.Bool("quiet", false, "Suppress the timing line and the --stats footer")
+	var dirFlags stringSlice
+	flag.Var(&dirFlags, "dir", "Directory to scan, relative to the working directory (repeatable or comma-separated; default src)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
@@ -134,39 +374,152 @@ func main() {
 		checkLines = defaultCheckLines
 	}
 
+	tag := strings.TrimPrefix(strings.TrimSpace(*tagPtr), "@")
+	if tag == "" {
+		tag = defaultTag
+	}
+	docTag = "@" + tag
+
+	format := strings.ToLower(strings.TrimSpace(*formatPtr))
+	if format != formatText && format != formatJSON && format != formatMarkdown {
+		fmt.Fprintf(os.Stderr, "Invalid --format value %q (expected text, json, or markdown)\n", *formatPtr)
+		os.Exit(exitError)
+	}
+
+	policy, err := scan.NewSeverityPolicy(*severityPtr, *failOnPtr, map[string]string{
+		kindMissing:     scan.SeverityWarning,
+		kindMisplaced:   scan.SeverityWarning,
+		kindEmpty:       scan.SeverityWarning,
+		kindMissingTags: scan.SeverityWarning,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
+	// Keep stdout clean for machine-readable formats
+	var logOut io.Writer = os.Stdout
+	if format != formatText {
+		logOut = os.Stderr
+	}
+
+	enabled, err := scan.ColorEnabled(*colorPtr, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	scan.SetColor(enabled && format == formatText)
+
+	excludes := scan.NewExcludes(exclude)
+
 	projectRoot, err := os.Getwd()
 	if err != nil {
 		fmt.Printf("Error getting current directory: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
-	srcDir := filepath.Join(projectRoot, "src")
+	if len(dirFlags) == 0 {
+		dirFlags = stringSlice{"src"}
+	}
 
-	info, err := os.Stat(srcDir)
-	if err != nil || !info.IsDir() {
-		fmt.Fprintf(os.Stderr, "Directory not found: %s\n", srcDir)
-		os.Exit(1)
+	var ignorePatterns []*regexp.Regexp
+	for _, glob := range ignoreGlobs {
+		ignorePatterns = append(ignorePatterns, scan.GlobToRegexp(glob))
 	}
 
-	files, err := collectSourceFiles(srcDir)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error collecting files: %v\n", err)
-		os.Exit(1)
+	var changedFiles map[string]bool
+	if *changedPtr || *stagedPtr {
+		changedFiles, err = gitChangedFiles(projectRoot, *changedBasePtr, *stagedPtr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing changed files: %v\n", err)
+			os.Exit(exitError)
+		}
 	}
 
-	var missingFiles []MissingFile
+	var files []string
+	seenFiles := make(map[string]bool)
+	// fileRoots maps each scanned file to the --dir it was found under
+	fileRoots := make(map[string]string)
+
+	for _, dir := range dirFlags {
+		srcDir := filepath.Join(projectRoot, dir)
 
-	for _, filePath := range files {
-		found, firstLine, err := hasFileOverview(filePath, checkLines)
+		info, err := os.Stat(srcDir)
+		if err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "Directory not found: %s\n", srcDir)
+			os.Exit(exitError)
+		}
+
+		dirFiles, err := scan.WalkSourceFiles([]string{srcDir}, supportedExtensions, excludes, *useGitignorePtr)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading file %s: %v\n", filePath, err)
-			continue
+			fmt.Fprintf(os.Stderr, "Error collecting files: %v\n", err)
+			os.Exit(exitError)
 		}
 
+		// Overlapping directories must not count a file twice
+		for _, f := range dirFiles {
+			if seenFiles[f] {
+				continue
+			}
+			seenFiles[f] = true
+
+			if changedFiles != nil {
+				relPath, _ := filepath.Rel(projectRoot, f)
+				if !changedFiles[filepath.ToSlash(relPath)] {
+					continue
+				}
+			}
+
+			if ignored, reason := shouldIgnoreFile(f, projectRoot, ignorePatterns); ignored {
+				if debug {
+					relPath, _ := filepath.Rel(projectRoot, f)
+					fmt.Fprintf(logOut, "Ignoring %s (%s)\n", filepath.ToSlash(relPath), reason)
+				}
+				continue
+			}
+			files = append(files, f)
+			fileRoots[f] = dir
+		}
+	}
+
+	opts := checkOptions{
+		linesToCheck:       checkLines,
+		requireDescription: *requireDescriptionPtr,
+		detectMisplaced:    *detectMisplacedPtr,
+		patterns:           buildPatternList(tag),
+		tagPattern:         regexp.MustCompile(`(?i)@\s*` + regexp.QuoteMeta(tag) + `\b`),
+	}
+	for _, tag := range requireTags {
+		tag = strings.TrimPrefix(tag, "@")
+		opts.requiredTags = append(opts.requiredTags, requiredTag{
+			name:    tag,
+			pattern: regexp.MustCompile(`(?i)@\s*` + regexp.QuoteMeta(tag) + `\b`),
+		})
+	}
+
+	var missingFiles []MissingFile
+	linesRead := 0
+
+	workers := *workersPtr
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results, err := checkFiles(files, opts, workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+		os.Exit(exitError)
+	}
+	// Results come back in file order so debug output stays deterministic
+	for i, res := range results {
+		filePath := files[i]
+		linesRead += res.lines
+
+		found, issue, firstLine := res.found, res.issue, res.firstLine
 		if found {
 			if debug {
 				relPath, _ := filepath.Rel(projectRoot, filePath)
-				fmt.Printf("Found @fileoverview in: %s\n", relPath)
+				fmt.Fprintf(logOut, "Found %s in: %s\n", docTag, relPath)
 			}
 			continue
 		}
@@ -176,23 +529,143 @@ func main() {
 
 		missingFiles = append(missingFiles, MissingFile{
 			File:      relPath,
+			Issue:     issue,
 			FirstLine: firstLine,
+			Severity:  policy.Severity(issueKind(issue)),
 		})
 	}
 
-	if len(missingFiles) == 0 {
-		fmt.Println("✅ All source files have @fileoverview documentation!")
-		fmt.Printf("✨ Done in %s\n", time.Since(start))
-		return
-	}
-
-	fmt.Println("📄 Files missing @fileoverview documentation:")
-
 	// Sort by filename
 	sort.Slice(missingFiles, func(i, j int) bool {
 		return missingFiles[i].File < missingFiles[j].File
 	})
 
+	summary := reportSummary{
+		totalFiles: len(files),
+		documented: len(files) - len(missingFiles),
+	}
+	if *byDirPtr {
+		summary.directories = computeDirCoverage(files, fileRoots, missingFiles, projectRoot)
+	}
+
+	if *baselinePtr != "" {
+		baselinePath := filepath.Join(projectRoot, *baselinePtr)
+
+		if *updateBaselinePtr {
+			baseline := scan.NewBaseline()
+			for _, mf := range missingFiles {
+				baseline.Add(mf.File, issueKind(mf.Issue))
+			}
+			if err := scan.WriteBaseline(baselinePath, baseline); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+				os.Exit(exitError)
+			}
+			fmt.Fprintf(logOut, "📝 Baseline %s updated with %d undocumented files.\n", *baselinePtr, len(missingFiles))
+			return
+		}
+
+		baseline, err := scan.LoadBaseline(baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading baseline: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		// Only issues the baseline does not already list for a file are reported
+		var newMissing []MissingFile
+		for _, mf := range missingFiles {
+			if !baseline.Take(mf.File, issueKind(mf.Issue)) {
+				newMissing = append(newMissing, mf)
+			}
+		}
+		summary.baselined = len(missingFiles) - len(newMissing)
+		missingFiles = newMissing
+	} else if *updateBaselinePtr {
+		fmt.Fprintln(os.Stderr, "--update-baseline requires --baseline=FILE")
+		os.Exit(exitError)
+	}
+
+	if *missingByDirPtr {
+		summary.missingByDir = computeMissingByDir(files, fileRoots, missingFiles, projectRoot)
+	}
+
+	switch format {
+	case formatJSON:
+		if err := printJSON(missingFiles, summary); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+			os.Exit(exitError)
+		}
+	case formatMarkdown:
+		printMarkdown(missingFiles, summary)
+	default:
+		printTable(missingFiles)
+		printMissingByDir(summary.missingByDir)
+		printCoverage(summary)
+		if !*quietPtr {
+			fmt.Printf("✨ Done in %s\n", time.Since(start))
+		}
+	}
+	if *statsPtr && !*quietPtr {
+		scan.PrintStats(logOut, "fileoverview", scan.Stats{
+			Files:   len(files),
+			Lines:   linesRead,
+			Matches: len(missingFiles),
+			Elapsed: time.Since(start),
+		})
+	}
+
+	if *fixPtr && len(missingFiles) > 0 {
+		var fixed int
+		fixed, missingFiles = fixMissing(missingFiles, projectRoot)
+		fmt.Fprintf(logOut, "🛠️  Added %s stubs to %d files.\n", docTag, fixed)
+	}
+
+	if *failOnMissingPtr && len(missingFiles) > *maxMissingPtr {
+		fmt.Fprintf(os.Stderr, "❌ %d files with %s issues exceeds the allowed maximum of %d\n", len(missingFiles), docTag, *maxMissingPtr)
+		os.Exit(exitFindings)
+	}
+
+	issueKinds := make([]string, len(missingFiles))
+	for i, mf := range missingFiles {
+		issueKinds[i] = issueKind(mf.Issue)
+	}
+	if count, failed := policy.Failing(issueKinds); failed {
+		fmt.Fprintf(os.Stderr, "❌ %d files with %s issues at %s severity or above exceeds the --fail-on maximum of %d\n", count, docTag, policy.FailOn, policy.FailMax)
+		os.Exit(exitFindings)
+	}
+}
+
+// fixMissing prepends a stub to every file whose issue is a missing header
+// and returns how many it fixed and the files it left alone. Files that
+// already have a header (empty description, missing tags, misplaced) are
+// never stubbed, so they keep counting towards --fail-on-missing along with
+// the files we failed to write.
+func fixMissing(missingFiles []MissingFile, projectRoot string) (int, []MissingFile) {
+	fixed := 0
+	var unfixed []MissingFile
+	for _, mf := range missingFiles {
+		if issueKind(mf.Issue) != kindMissing {
+			unfixed = append(unfixed, mf)
+			continue
+		}
+		if err := insertStub(filepath.Join(projectRoot, mf.File)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding stub to %s: %v\n", mf.File, err)
+			unfixed = append(unfixed, mf)
+			continue
+		}
+		fixed++
+	}
+	return fixed, unfixed
+}
+
+// printTable writes the aligned missing-files table (the default text format)
+func printTable(missingFiles []MissingFile) {
+	if len(missingFiles) == 0 {
+		fmt.Printf("✅ All source files have %s documentation!\n", docTag)
+		return
+	}
+
+	fmt.Println(scan.Colorize(fmt.Sprintf("📄 Files missing %s documentation:", docTag), scan.AnsiBold))
+
 	// Calculate max length for padding
 	maxFileLength := len("File")
 	for _, mf := range missingFiles {
@@ -201,14 +674,393 @@ func main() {
 		}
 	}
 
+	maxIssueLength := len("Issue")
+	for _, mf := range missingFiles {
+		if len(mf.Issue) > maxIssueLength {
+			maxIssueLength = len(mf.Issue)
+		}
+	}
+
 	// Print Table
-	fmt.Printf("%-*s  First line\n", maxFileLength, "File")
-	fmt.Printf("%-*s  ----------\n", maxFileLength, strings.Repeat("-", maxFileLength))
+	fmt.Printf("%-*s  %-*s  First line\n", maxFileLength, "File", maxIssueLength, "Issue")
+	fmt.Printf("%s  %s  ----------\n", strings.Repeat("-", maxFileLength), strings.Repeat("-", maxIssueLength))
+
+	for _, mf := range missingFiles {
+		// Pad before coloring so the escape codes do not count towards the width
+		issueStyle := scan.AnsiYellow
+		if mf.Issue == issueMissing {
+			issueStyle = scan.AnsiRed
+		}
+		fmt.Printf("%s  %s  %s\n",
+			scan.Colorize(fmt.Sprintf("%-*s", maxFileLength, mf.File), scan.AnsiCyan),
+			scan.Colorize(fmt.Sprintf("%-*s", maxIssueLength, mf.Issue), issueStyle),
+			mf.FirstLine)
+	}
+
+	fmt.Printf("Found %d files missing %s documentation.\n", len(missingFiles), docTag)
+}
+
+// printJSON writes the missing files and totals as an indented JSON document
+func printJSON(missingFiles []MissingFile, summary reportSummary) error {
+	report := jsonReport{
+		TotalFiles:      summary.totalFiles,
+		DocumentedCount: summary.documented,
+		MissingCount:    len(missingFiles),
+		BaselinedCount:  summary.baselined,
+		CoveragePercent: coveragePercent(summary.documented, summary.totalFiles),
+		Missing:         missingFiles,
+		Directories:     summary.directories,
+		MissingByDir:    summary.missingByDir,
+	}
+	if report.Missing == nil {
+		report.Missing = []MissingFile{}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// printMarkdown renders the missing files and coverage as GitHub-flavored Markdown
+func printMarkdown(missingFiles []MissingFile, summary reportSummary) {
+	fmt.Printf("## %s coverage\n", docTag)
+	fmt.Println()
+	fmt.Printf("**Coverage: %.1f%%** (%d of %d files documented)\n", coveragePercent(summary.documented, summary.totalFiles), summary.documented, summary.totalFiles)
+	if summary.baselined > 0 {
+		fmt.Println()
+		fmt.Printf("_%d known undocumented files are suppressed by the baseline._\n", summary.baselined)
+	}
+
+	if len(summary.directories) > 0 {
+		fmt.Println()
+		fmt.Println("| Directory | Documented | Total | Coverage |")
+		fmt.Println("| --- | ---: | ---: | ---: |")
+		for _, dc := range summary.directories {
+			fmt.Printf("| %s | %d | %d | %.1f%% |\n", escapeMarkdownCell(dc.Dir), dc.Documented, dc.Total, dc.Percent)
+		}
+	}
+
+	fmt.Println()
+	if len(missingFiles) == 0 {
+		fmt.Printf("✅ All source files have %s documentation!\n", docTag)
+		return
+	}
+
+	fmt.Printf("### Files missing %s (%d)\n", docTag, len(missingFiles))
+	fmt.Println()
+	fmt.Println("| File | Issue | First line |")
+	fmt.Println("| --- | --- | --- |")
+	for _, mf := range missingFiles {
+		firstLine := ""
+		if mf.FirstLine != "" {
+			firstLine = "`" + strings.ReplaceAll(escapeMarkdownCell(mf.FirstLine), "`", "'") + "`"
+		}
+		fmt.Printf("| `%s` | %s | %s |\n", escapeMarkdownCell(mf.File), escapeMarkdownCell(mf.Issue), firstLine)
+	}
+
+	if len(summary.missingByDir) > 0 {
+		fmt.Println()
+		fmt.Println("### Missing by directory")
+		fmt.Println()
+		fmt.Println("| Directory | Missing |")
+		fmt.Println("| --- | ---: |")
+		for _, dm := range summary.missingByDir {
+			fmt.Printf("| %s | %d |\n", escapeMarkdownCell(dm.Dir), dm.Missing)
+		}
+	}
+}
+
+// escapeMarkdownCell keeps pipes from splitting a table cell
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+// coveragePercent returns documented/total as a percentage (100 for no files)
+func coveragePercent(documented, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(documented) / float64(total) * 100
+}
 
+// topLevelDir returns the scan root joined with the file's first directory
+// below it (e.g. src/main); files directly in the root map to the root itself
+func topLevelDir(filePath, root, projectRoot string) string {
+	absRoot := filepath.Join(projectRoot, root)
+	rel, err := filepath.Rel(absRoot, filePath)
+	root = filepath.ToSlash(filepath.Clean(root))
+	if err != nil {
+		return root
+	}
+	rel = filepath.ToSlash(rel)
+	if idx := strings.Index(rel, "/"); idx != -1 {
+		return root + "/" + rel[:idx]
+	}
+	return root
+}
+
+// computeDirCoverage aggregates documented/total counts per top-level directory
+func computeDirCoverage(files []string, fileRoots map[string]string, missingFiles []MissingFile, projectRoot string) []DirCoverage {
+	missing := make(map[string]bool, len(missingFiles))
 	for _, mf := range missingFiles {
-		fmt.Printf("%-*s  %s\n", maxFileLength, mf.File, mf.FirstLine)
+		missing[mf.File] = true
+	}
+
+	byDir := make(map[string]*DirCoverage)
+	for _, f := range files {
+		dir := topLevelDir(f, fileRoots[f], projectRoot)
+		cov, ok := byDir[dir]
+		if !ok {
+			cov = &DirCoverage{Dir: dir}
+			byDir[dir] = cov
+		}
+		cov.Total++
+
+		relPath, _ := filepath.Rel(projectRoot, f)
+		if !missing[filepath.ToSlash(relPath)] {
+			cov.Documented++
+		}
+	}
+
+	result := make([]DirCoverage, 0, len(byDir))
+	for _, cov := range byDir {
+		cov.Percent = coveragePercent(cov.Documented, cov.Total)
+		result = append(result, *cov)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Dir < result[j].Dir
+	})
+	return result
+}
+
+// computeMissingByDir counts the reported files per top-level directory,
+// sorted by missing count descending (then by name)
+func computeMissingByDir(files []string, fileRoots map[string]string, missingFiles []MissingFile, projectRoot string) []DirMissing {
+	dirOf := make(map[string]string, len(files))
+	for _, f := range files {
+		relPath, _ := filepath.Rel(projectRoot, f)
+		dirOf[filepath.ToSlash(relPath)] = topLevelDir(f, fileRoots[f], projectRoot)
+	}
+
+	counts := make(map[string]int)
+	for _, mf := range missingFiles {
+		counts[dirOf[mf.File]]++
+	}
+
+	result := make([]DirMissing, 0, len(counts))
+	for dir, count := range counts {
+		result = append(result, DirMissing{Dir: dir, Missing: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Missing == result[j].Missing {
+			return result[i].Dir < result[j].Dir
+		}
+		return result[i].Missing > result[j].Missing
+	})
+	return result
+}
+
+// printMissingByDir writes the per-directory missing summary table
+func printMissingByDir(missingByDir []DirMissing) {
+	if len(missingByDir) == 0 {
+		return
 	}
 
-	fmt.Printf("Found %d files missing @fileoverview documentation.\n", len(missingFiles))
-	fmt.Printf("✨ Done in %s\n", time.Since(start))
-}
\ No newline at end of file
+	maxDirLength := len("Directory")
+	for _, dm := range missingByDir {
+		if len(dm.Dir) > maxDirLength {
+			maxDirLength = len(dm.Dir)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(scan.Colorize("Missing by directory:", scan.AnsiBold))
+	fmt.Printf("%-*s  Missing\n", maxDirLength, "Directory")
+	fmt.Printf("%s  -------\n", strings.Repeat("-", maxDirLength))
+	for _, dm := range missingByDir {
+		fmt.Printf("%-*s  %7d\n", maxDirLength, dm.Dir, dm.Missing)
+	}
+	fmt.Println()
+}
+
+// printCoverage writes the headline coverage line and the optional per-directory table
+func printCoverage(summary reportSummary) {
+	fmt.Println(scan.Colorize(fmt.Sprintf("Coverage: %.1f%% (%d of %d files documented)", coveragePercent(summary.documented, summary.totalFiles), summary.documented, summary.totalFiles), scan.AnsiBold))
+	if summary.baselined > 0 {
+		fmt.Printf("Baseline: %d known undocumented files suppressed\n", summary.baselined)
+	}
+
+	dirCoverage := summary.directories
+	if len(dirCoverage) == 0 {
+		return
+	}
+
+	maxDirLength := len("Directory")
+	for _, dc := range dirCoverage {
+		if len(dc.Dir) > maxDirLength {
+			maxDirLength = len(dc.Dir)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("%-*s  Documented  Coverage\n", maxDirLength, "Directory")
+	fmt.Printf("%s  ----------  --------\n", strings.Repeat("-", maxDirLength))
+	for _, dc := range dirCoverage {
+		counts := fmt.Sprintf("%d/%d", dc.Documented, dc.Total)
+		fmt.Printf("%-*s  %10s  %7.1f%%\n", maxDirLength, dc.Dir, counts, dc.Percent)
+	}
+}
+
+// gitChangedFiles returns the supported source files changed between base and
+// HEAD, or with staged the files staged for commit, as paths relative to
+// projectRoot
+func gitChangedFiles(projectRoot, base string, staged bool) (map[string]bool, error) {
+	revision := base + "...HEAD"
+	if staged {
+		revision = "--cached"
+	}
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", revision, "--")
+	cmd.Dir = projectRoot
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s: %s", revision, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && supportedExtensions[filepath.Ext(line)] {
+			changed[filepath.ToSlash(line)] = true
+		}
+	}
+	return changed, nil
+}
+
+// stubOverview is the placeholder header written by --fix (%s is the tag)
+const stubOverview = "/**\n * %s TODO: describe this module.\n */\n"
+
+// insertStub prepends stubOverview to a file, keeping a leading shebang and
+// 'use strict' directive above it. The file is replaced atomically.
+func insertStub(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+
+	// Find the end of the preamble (shebang, then an optional 'use strict')
+	offset := 0
+	rest := content
+	nextLine := func() (string, int) {
+		idx := strings.Index(rest, "\n")
+		if idx == -1 {
+			return rest, len(rest)
+		}
+		return rest[:idx], idx + 1
+	}
+
+	if strings.HasPrefix(rest, "#!") {
+		_, n := nextLine()
+		offset += n
+		rest = rest[n:]
+	}
+	if line, n := nextLine(); isUseStrict(line) {
+		offset += n
+		rest = rest[n:]
+	}
+
+	preamble := content[:offset]
+	if preamble != "" && !strings.HasSuffix(preamble, "\n") {
+		preamble += newline
+	}
+	stub := strings.ReplaceAll(fmt.Sprintf(stubOverview, docTag), "\n", newline)
+	updated := preamble + stub + content[offset:]
+
+	return writeFileAtomic(filePath, []byte(updated))
+}
+
+func isUseStrict(line string) bool {
+	trimmed := strings.TrimSuffix(strings.TrimSpace(line), ";")
+	return trimmed == "'use strict'" || trimmed == `"use strict"`
+}
+
+// writeFileAtomic writes to a temp file in the same directory and renames it
+// over the target so readers never observe a partially written file
+func writeFileAtomic(filePath string, data []byte) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, filePath)
+}
+
+// shouldIgnoreFile reports whether a file is exempt from documentation:
+// declaration files, index files that only re-export, and --ignore-glob matches
+func shouldIgnoreFile(filePath, projectRoot string, ignorePatterns []*regexp.Regexp) (bool, string) {
+	name := filepath.Base(filePath)
+	if strings.HasSuffix(name, ".d.ts") {
+		return true, "declaration file"
+	}
+
+	relPath, err := filepath.Rel(projectRoot, filePath)
+	if err != nil {
+		relPath = filePath
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	for _, pattern := range ignorePatterns {
+		if pattern.MatchString(relPath) || pattern.MatchString(name) {
+			return true, "matches --ignore-glob"
+		}
+	}
+
+	if strings.TrimSuffix(name, filepath.Ext(name)) == "index" && isBarrelFile(filePath) {
+		return true, "re-export barrel"
+	}
+
+	return false, ""
+}
+
+// isBarrelFile reports whether a file contains nothing but import/export-from
+// statements and comments. Unreadable files are treated as non-barrels.
+func isBarrelFile(filePath string) bool {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return false
+	}
+
+	code := commentPattern.ReplaceAllString(string(data), "")
+	if !reexportPattern.MatchString(code) {
+		return false
+	}
+	rest := reexportPattern.ReplaceAllString(code, "")
+	return strings.TrimSpace(rest) == ""
+}
@@ -0,0 +1,115 @@
+//go:build ignore
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Run with: go test scripts/check-fileoverview.go scripts/check-fileoverview_test.go
+
+func TestFixMissingOnlyStubsMissingHeaders(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"missing.ts":      "export const a = 1;\n",
+		"empty.ts":        "/**\n * @fileoverview\n */\nexport const b = 2;\n",
+		"missing-tags.ts": "/**\n * @fileoverview Has a description.\n */\nexport const c = 3;\n",
+		"misplaced.ts":    "// license\n// line 2\n// line 3\n/** @fileoverview Too far down. */\nexport const d = 4;\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tag := defaultTag
+	opts := checkOptions{
+		linesToCheck:       3,
+		requireDescription: true,
+		detectMisplaced:    true,
+		patterns:           buildPatternList(tag),
+		tagPattern:         regexp.MustCompile(`(?i)@\s*` + regexp.QuoteMeta(tag) + `\b`),
+		requiredTags: []requiredTag{
+			{name: "module", pattern: regexp.MustCompile(`(?i)@\s*module\b`)},
+		},
+	}
+
+	wantKinds := map[string]string{
+		"missing.ts":      kindMissing,
+		"empty.ts":        kindEmpty,
+		"missing-tags.ts": kindMissingTags,
+		"misplaced.ts":    kindMisplaced,
+	}
+	var missingFiles []MissingFile
+	for name := range files {
+		found, issue, _, _, err := hasFileOverview(filepath.Join(dir, name), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Fatalf("%s unexpectedly passed the check", name)
+		}
+		if got := issueKind(issue); got != wantKinds[name] {
+			t.Fatalf("%s: issue %q has kind %s, want %s", name, issue, got, wantKinds[name])
+		}
+		missingFiles = append(missingFiles, MissingFile{File: name, Issue: issue})
+	}
+
+	fixed, unfixed := fixMissing(missingFiles, dir)
+	if fixed != 1 {
+		t.Errorf("fixed = %d, want 1", fixed)
+	}
+	if len(unfixed) != 3 {
+		t.Errorf("got %d unfixed files, want 3: %+v", len(unfixed), unfixed)
+	}
+	for _, mf := range unfixed {
+		if mf.File == "missing.ts" {
+			t.Errorf("missing.ts was left unfixed")
+		}
+	}
+
+	for name, original := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(data)
+		if name == "missing.ts" {
+			if strings.Count(content, "@fileoverview") != 1 || !strings.HasSuffix(content, original) {
+				t.Errorf("missing.ts was not stubbed once:\n%s", content)
+			}
+			continue
+		}
+		if content != original {
+			t.Errorf("%s was modified by --fix:\n%s", name, content)
+		}
+	}
+}
+
+func TestCheckFilesFailsOnUnreadableFile(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.ts")
+	if err := os.WriteFile(good, []byte("/** @fileoverview Fine. */\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "broken.ts")
+	if err := os.Symlink(filepath.Join(dir, "missing.ts"), broken); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := checkOptions{linesToCheck: 20, patterns: buildPatternList(defaultTag)}
+	if _, err := checkFiles([]string{good, broken}, opts, 2); err == nil {
+		t.Fatal("checkFiles succeeded on a dangling symlink, want an error")
+	}
+	results, err := checkFiles([]string{good}, opts, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].found {
+		t.Errorf("good.ts: got issue %q, want a header", results[0].issue)
+	}
+}
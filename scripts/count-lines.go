@@ -1,100 +1,606 @@
+//go:build ignore
+
 package main
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
 )
 
 type fileCount struct {
 	path  string
 	lines int
+	lineBreakdown
+	// declarations counts functions, methods, and classes (--count=functions)
+	declarations int
+}
+
+// lineBreakdown splits a file's lines cloc-style; a line holding both code
+// and a comment counts as code
+type lineBreakdown struct {
+	code    int
+	comment int
+	blank   int
+}
+
+// options holds the parsed command-line flags
+type options struct {
+	minLines     int
+	breakdown    bool
+	extensions   map[string]bool
+	useGitignore bool
+	format       string
+	byDir        bool
+	maxLines     int
+	failOver     bool
+	sortOrder    string
+	workers      int
+	countMode    string
+	comparePath  string
+	dirs         []string
+	histogram    bool
+	filter       pathFilter
+	trimBlanks   bool
+	outPath      string
+	stdinPaths   bool
+	changed      bool
+	changedBase  string
+	staged       bool
+	color        string
+	// baselinePath names the --max-lines ratchet file; see checkMaxLines
+	baselinePath   string
+	updateBaseline bool
+	stats          bool
+	quiet          bool
+	// severity assigns the over-limit level gated by --fail-on
+	severity scan.SeverityPolicy
+}
+
+// pathFilter holds the --include globs and the --exclude list, matched
+// against slash paths relative to the working directory
+type pathFilter struct {
+	include []*regexp.Regexp
+	exclude scan.Excludes
+}
+
+// allows reports whether a file passes the filter: it must match some
+// --include glob (when any are given) and not be excluded
+func (f pathFilter) allows(relPath string) bool {
+	return f.includes(relPath) && !f.exclude.Match(relPath)
+}
+
+// includes reports whether a file matches some --include glob, or none were given
+func (f pathFilter) includes(relPath string) bool {
+	return len(f.include) == 0 || matchesAnyGlob(relPath, f.include)
+}
+
+func matchesAnyGlob(path string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Metrics accepted by --count
+const (
+	countLinesMode     = "lines"
+	countFunctionsMode = "functions"
+)
+
+// Heuristics for --count=functions, applied to code lines only
+var (
+	functionKeywordRegex = regexp.MustCompile(`\bfunction\b`)
+	classRegex           = regexp.MustCompile(`\bclass\s+[A-Za-z_$]`)
+	arrowAssignRegex     = regexp.MustCompile(`^(?:export\s+)?(?:(?:const|let|var)\s+)?[A-Za-z_$#][\w$]*\s*(?::[^=]+)?=\s*(?:async\s+)?(?:\([^)]*\)|[A-Za-z_$][\w$]*)\s*(?::[^=]+)?=>`)
+	methodRegex          = regexp.MustCompile(`^(?:(?:public|private|protected|static|async|readonly|override|abstract|get|set)\s+)*\*?[A-Za-z_$#][\w$]*\s*(?:<[^>]*>)?\s*\([^)]*\)\s*(?::\s*[^{;]+)?\{\s*$`)
+	notMethodRegex       = regexp.MustCompile(`^(?:if|for|while|switch|catch|with|return|function)\b`)
+)
+
+// Sort orders accepted by --sort
+const (
+	sortLinesDesc = "lines-desc"
+	sortLinesAsc  = "lines-asc"
+	sortPath      = "path"
+)
+
+// dirCount sums the lines of the files under one top-level directory
+type dirCount struct {
+	Dir   string `json:"dir"`
+	Files int    `json:"files"`
+	Lines int    `json:"lines"`
+}
+
+// histogramBucket counts the files whose line count falls in [Min, Max);
+// Max is 0 for the open-ended last bucket
+type histogramBucket struct {
+	Label string `json:"label"`
+	Min   int    `json:"min"`
+	Max   int    `json:"max,omitempty"`
+	Files int    `json:"files"`
+}
+
+// histogramBounds are the lower bounds of the --histogram buckets
+var histogramBounds = []int{0, 50, 200, 500}
+
+// Exit codes shared by every scanner
+const (
+	exitFindings = 1 // findings over a --fail-on-* / --max threshold
+	exitError    = 2 // invalid usage or a runtime/I/O error
+)
+
+// kindOverLimit is the kind of a file over --max-lines, for --severity
+const kindOverLimit = "over-limit"
+
+// baselineKey holds the line count of a file in the --baseline ratchet
+const baselineKey = "lines"
+
+// Output formats accepted by --format
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatMarkdown = "markdown"
+)
+
+// jsonFile is one entry of the --format=json report
+type jsonFile struct {
+	Path         string         `json:"path"`
+	Lines        int            `json:"lines"`
+	Breakdown    *jsonBreakdown `json:"breakdown,omitempty"`
+	Declarations *int           `json:"declarations,omitempty"`
+}
+
+type jsonBreakdown struct {
+	Code    int `json:"code"`
+	Comment int `json:"comment"`
+	Blank   int `json:"blank"`
+}
+
+// jsonReport is the document written by --format=json
+type jsonReport struct {
+	Files      []jsonFile        `json:"files"`
+	TotalFiles int               `json:"totalFiles"`
+	TotalLines int               `json:"totalLines"`
+	Breakdown  *jsonBreakdown    `json:"breakdown,omitempty"`
+	Dirs       []dirCount        `json:"directories,omitempty"`
+	Histogram  []histogramBucket `json:"histogram,omitempty"`
+	// TotalDeclarations is set with --count=functions
+	TotalDeclarations *int `json:"totalDeclarations,omitempty"`
+}
+
+// Change statuses reported by --compare
+const (
+	statusAdded   = "added"
+	statusDeleted = "deleted"
+	statusChanged = "changed"
+)
+
+// fileDelta is one file whose line count differs from the --compare snapshot
+type fileDelta struct {
+	Path   string `json:"path"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+	Delta  int    `json:"delta"`
+	Status string `json:"status"`
+}
+
+// compareReport is the document written by --compare with --format=json
+type compareReport struct {
+	Snapshot    string      `json:"snapshot"`
+	Files       []fileDelta `json:"files"`
+	Added       int         `json:"added"`
+	Deleted     int         `json:"deleted"`
+	Changed     int         `json:"changed"`
+	TotalBefore int         `json:"totalBefore"`
+	TotalAfter  int         `json:"totalAfter"`
+	NetChange   int         `json:"netChange"`
+}
+
+// stringSlice handles comma-separated flags or multiple flag occurrences
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	parts := strings.Split(value, ",")
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			*s = append(*s, trimmed)
+		}
+	}
+	return nil
+}
+
+// hashCommentExtensions use `#` line comments instead of `//` and `/* */`
+var hashCommentExtensions = map[string]bool{
+	".sh":   true,
+	".py":   true,
+	".rb":   true,
+	".yml":  true,
+	".yaml": true,
+	".toml": true,
 }
 
 func main() {
-	minLines, err := parseArgs(os.Args[1:])
+	start := time.Now()
+	opts, err := parseArgs(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
+	minLines := opts.minLines
 
 	projectRoot, err := os.Getwd()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to get working directory: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
-	srcDir := filepath.Join(projectRoot, "src")
-	stat, err := os.Stat(srcDir)
-	if err != nil || !stat.IsDir() {
-		fmt.Fprintf(os.Stderr, "Directory not found: %s\n", srcDir)
-		os.Exit(1)
+	var files []string
+	var roots []string
+	if opts.stdinPaths {
+		files, err = readPathList(os.Stdin, projectRoot, opts.extensions, opts.filter)
+		if err == nil && opts.useGitignore {
+			files, err = scan.KeepGitFiles(projectRoot, files)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read paths from stdin: %v\n", err)
+			os.Exit(exitError)
+		}
+		roots = []string{"."}
+	} else {
+		files, roots = collectDirFiles(opts, projectRoot)
 	}
 
-	tsFiles, err := collectTsFiles(srcDir)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to collect TypeScript files: %v\n", err)
-		os.Exit(1)
+	if opts.changed || opts.staged {
+		changedFiles, err := gitChangedFiles(projectRoot, opts.changedBase, opts.staged)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to list changed files: %v\n", err)
+			os.Exit(exitError)
+		}
+		kept := files[:0]
+		for _, path := range files {
+			rel, _ := filepath.Rel(projectRoot, path)
+			if changedFiles[filepath.ToSlash(rel)] {
+				kept = append(kept, path)
+			}
+		}
+		files = kept
 	}
 
-	counts, err := countLinesForFiles(tsFiles, projectRoot)
+	settings := countSettings{
+		declarations:       opts.countMode == countFunctionsMode,
+		trimTrailingBlanks: opts.trimBlanks,
+	}
+	counts, err := countLinesForFiles(files, projectRoot, opts.workers, settings)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to count lines: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
-	sort.Slice(counts, func(i, j int) bool {
-		return counts[i].lines > counts[j].lines
-	})
+	baseline := scan.NewBaseline()
+	if opts.updateBaseline {
+		for _, c := range counts {
+			if c.lines > opts.maxLines {
+				baseline.Set(c.path, baselineKey, c.lines)
+			}
+		}
+		if err := scan.WriteBaseline(opts.baselinePath, baseline); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write baseline: %v\n", err)
+			os.Exit(exitError)
+		}
+		fmt.Printf("📝 Baseline %s updated with %d files over %d lines.\n", opts.baselinePath, len(baseline), opts.maxLines)
+		return
+	}
+	if opts.baselinePath != "" {
+		baseline, err = scan.LoadBaseline(opts.baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read baseline: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	sortCounts(counts, opts.sortOrder, opts.countMode == countFunctionsMode)
 
 	filtered := filterByMinLines(counts, minLines)
 
-	if minLines > 0 {
-		fmt.Printf("Showing files with %d+ lines (%d of %d total)\n\n", minLines, len(filtered), len(counts))
+	var dirs []dirCount
+	if opts.byDir {
+		dirs = countByDir(filtered, roots)
 	}
 
-	printTable(filtered)
-}
+	var histogram []histogramBucket
+	if opts.histogram {
+		histogram = buildHistogram(filtered)
+	}
 
-func parseArgs(args []string) (int, error) {
-	minLines := 0
+	cols := reportColumns{
+		breakdown:    opts.breakdown,
+		declarations: opts.countMode == countFunctionsMode,
+	}
 
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
+	var report *compareReport
+	if opts.comparePath != "" {
+		// The snapshot holds only the files it showed, so take both runs with
+		// the same --min-lines and --ext for a like-for-like diff
+		previous, err := loadSnapshot(opts.comparePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load --compare snapshot: %v\n", err)
+			os.Exit(exitError)
+		}
+		compared := compareCounts(previous, filtered)
+		compared.Snapshot = opts.comparePath
+		report = &compared
+	}
 
-		if arg == "--min-lines" {
-			if i+1 >= len(args) {
-				return 0, errors.New("missing value for --min-lines")
-			}
-			value := args[i+1]
-			parsed, err := parsePositiveInt(value)
-			if err != nil {
-				return 0, fmt.Errorf("invalid value for --min-lines: %s", value)
-			}
-			minLines = parsed
-			i++
-			continue
+	// Informational lines share stdout with the text table, but never end up
+	// in a machine-readable report or an --out file
+	out := io.Writer(os.Stdout)
+	var logOut io.Writer = os.Stdout
+	if opts.format != formatText {
+		logOut = os.Stderr
+	}
+	var outFile *os.File
+	if opts.outPath != "" {
+		outFile, err = os.Create(opts.outPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create --out file: %v\n", err)
+			os.Exit(exitError)
 		}
+		out = outFile
+		logOut = os.Stderr
+	}
+	// --color was validated by parseArgs; an --out file is never colored
+	enabled, _ := scan.ColorEnabled(opts.color, os.Stdout)
+	scan.SetColor(enabled && opts.format == formatText && outFile == nil)
 
-		if strings.HasPrefix(arg, "--min-lines=") {
-			value := strings.TrimPrefix(arg, "--min-lines=")
-			parsed, err := parsePositiveInt(value)
-			if err != nil {
-				return 0, fmt.Errorf("invalid value for --min-lines: %s", value)
-			}
-			minLines = parsed
-			continue
+	if minLines > 0 && report == nil && !opts.quiet {
+		fmt.Fprintf(logOut, "Showing files with %d+ lines (%d of %d total)\n", minLines, len(filtered), len(counts))
+		if logOut == out {
+			fmt.Fprintln(out)
 		}
+	}
 
-		return 0, fmt.Errorf("unknown argument: %s", arg)
+	if report != nil {
+		err = writeCompareReport(out, opts.format, *report)
+	} else {
+		if opts.format == formatText {
+			// Shares are relative to every counted file, not just those shown
+			cols.shareOf = sumCounts(counts).lines
+		}
+		err = writeReport(out, opts, filtered, dirs, histogram, cols)
+	}
+	if outFile != nil {
+		if closeErr := outFile.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", opts.format, err)
+		os.Exit(exitError)
 	}
 
-	return minLines, nil
+	overLimit := 0
+	if opts.maxLines > 0 {
+		overLimit = checkMaxLines(counts, opts.maxLines, baseline)
+	}
+	if opts.stats && !opts.quiet {
+		scan.PrintStats(logOut, "lines", scan.Stats{
+			Files:   len(counts),
+			Lines:   sumCounts(counts).lines,
+			Matches: len(filtered),
+			Elapsed: time.Since(start),
+		})
+	}
+	if overLimit > 0 && opts.failOver {
+		os.Exit(exitFindings)
+	}
+	kinds := make([]string, overLimit)
+	for i := range kinds {
+		kinds[i] = kindOverLimit
+	}
+	if count, failed := opts.severity.Failing(kinds); failed {
+		fmt.Fprintf(os.Stderr, "❌ %d files over --max-lines at %s severity or above exceeds the --fail-on maximum of %d\n", count, opts.severity.FailOn, opts.severity.FailMax)
+		os.Exit(exitFindings)
+	}
+}
+
+// writeReport writes the shown files in the selected --format to w
+func writeReport(w io.Writer, opts options, filtered []fileCount, dirs []dirCount, histogram []histogramBucket, cols reportColumns) error {
+	switch opts.format {
+	case formatJSON:
+		return printJSON(w, filtered, dirs, histogram, cols)
+	case formatCSV:
+		return printCSV(w, filtered, cols)
+	case formatMarkdown:
+		printMarkdown(w, filtered, dirs, histogram, cols)
+		return nil
+	}
+
+	printTable(w, filtered, cols)
+	if opts.byDir {
+		printDirTable(w, dirs)
+	}
+	if opts.histogram {
+		printHistogram(w, histogram, len(filtered))
+	}
+	return nil
+}
+
+// writeCompareReport writes the --compare deltas in the selected --format to w
+func writeCompareReport(w io.Writer, format string, report compareReport) error {
+	switch format {
+	case formatJSON:
+		return printCompareJSON(w, report)
+	case formatCSV:
+		return printCompareCSV(w, report)
+	case formatMarkdown:
+		printCompareMarkdown(w, report)
+	default:
+		printCompareTable(w, report)
+	}
+	return nil
+}
+
+func parseArgs(args []string) (options, error) {
+	var opts options
+	var minLines string
+	var maxLines string
+	var severityFlag string
+	var failOn string
+	var extensions stringSlice
+	var include stringSlice
+	var exclude stringSlice
+	var dirs stringSlice
+
+	fs := flag.NewFlagSet("count-lines", flag.ContinueOnError)
+	// Errors are reported by main; only -h/--help prints the usage
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&minLines, "min-lines", "0", "Only show files with at least N lines")
+	fs.StringVar(&maxLines, "max-lines", "0", "Report files with more than N lines (0 disables the check)")
+	fs.BoolVar(&opts.failOver, "fail-over", false, "Exit with code 1 when any file exceeds --max-lines")
+	fs.BoolVar(&opts.failOver, "fail-on-match", false, "Alias for --fail-over")
+	fs.StringVar(&severityFlag, "severity", "", "Severity per finding kind as kind=error|warning|info, e.g. over-limit=error (default warning)")
+	fs.StringVar(&failOn, "fail-on", "", "Exit with code 1 when more than N files over --max-lines (default 0) are at LEVEL severity or above, as LEVEL[:N]")
+	fs.BoolVar(&opts.trimBlanks, "trim-trailing-blanks", false, "Ignore blank lines at the end of each file")
+	fs.BoolVar(&opts.breakdown, "breakdown", false, "Split each count into code, comment, and blank lines")
+	fs.BoolVar(&opts.stdinPaths, "stdin-paths", false, "Count the newline-delimited file paths read from stdin instead of walking --dir")
+	fs.Var(&dirs, "dir", "Directory to count, relative to the working directory (repeatable or comma-separated; default src)")
+	fs.Var(&extensions, "ext", "File extensions to count (repeatable or comma-separated; default .ts)")
+	fs.Var(&include, "include", "Only count files matching these globs, e.g. **/*.component.ts (repeatable or comma-separated)")
+	fs.Var(&exclude, "exclude", "Skip files matching these globs, e.g. **/*.spec.ts; a bare name such as fixtures skips directories with that name, in addition to node_modules, dist, and the other defaults (repeatable or comma-separated)")
+	fs.BoolVar(&opts.histogram, "histogram", false, "Summarize how many files fall into each size bucket (0-49, 50-199, 200-499, 500+ lines)")
+	fs.BoolVar(&opts.byDir, "by-dir", false, "Summarize line totals per top-level directory under each --dir, largest first")
+	fs.StringVar(&opts.countMode, "count", countLinesMode, "Metric to rank by: lines or functions (function, method, and class declarations)")
+	fs.IntVar(&opts.workers, "workers", runtime.NumCPU(), "Number of worker goroutines counting files in parallel")
+	fs.StringVar(&opts.sortOrder, "sort", sortLinesDesc, "Sort order: lines-desc, lines-asc, or path")
+	fs.StringVar(&opts.format, "format", formatText, "Output format: text, json, csv, or markdown")
+	fs.StringVar(&opts.comparePath, "compare", "", "Show per-file line deltas against a previous --format=json snapshot")
+	fs.StringVar(&opts.outPath, "out", "", "Write the report to this file instead of stdout; informational lines go to stderr")
+	fs.BoolVar(&opts.useGitignore, "use-gitignore", false, "Skip files ignored by git (.gitignore, .git/info/exclude, global excludes)")
+	fs.BoolVar(&opts.changed, "changed", false, "Only count files changed on this branch (git diff --name-only <base>...HEAD)")
+	fs.StringVar(&opts.changedBase, "changed-base", "origin/main", "Base ref used by --changed")
+	fs.BoolVar(&opts.staged, "staged", false, "Only count files staged for commit (git diff --cached --name-only), e.g. from a pre-commit hook")
+	fs.StringVar(&opts.baselinePath, "baseline", "", "Baseline JSON of files known to exceed --max-lines and their line counts; they only fail --max-lines once they grow")
+	fs.BoolVar(&opts.updateBaseline, "update-baseline", false, "Rewrite the --baseline file with the files currently over --max-lines and exit")
+	fs.StringVar(&opts.color, "color", scan.ColorAuto, "Color the text report on stdout: auto (terminal without NO_COLOR), always, or never")
+	fs.BoolVar(&opts.stats, "stats", false, "Print a footer with files counted, total lines, files shown, and elapsed time")
+	fs.BoolVar(&opts.quiet, "quiet", false, "Suppress the --min-lines note and the --stats footer")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fs.SetOutput(os.Stderr)
+			fmt.Fprintln(os.Stderr, "Usage of count-lines:")
+			fs.PrintDefaults()
+		}
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unknown argument: %s", fs.Arg(0))
+	}
+
+	parsed, err := parsePositiveInt(minLines)
+	if err != nil {
+		return opts, fmt.Errorf("invalid value for --min-lines: %s", minLines)
+	}
+	opts.minLines = parsed
+
+	if opts.workers <= 0 {
+		opts.workers = runtime.NumCPU()
+	}
+
+	parsed, err = parsePositiveInt(maxLines)
+	if err != nil {
+		return opts, fmt.Errorf("invalid value for --max-lines: %s", maxLines)
+	}
+	opts.maxLines = parsed
+	if opts.failOver && opts.maxLines == 0 {
+		return opts, errors.New("--fail-over requires --max-lines")
+	}
+	opts.severity, err = scan.NewSeverityPolicy(severityFlag, failOn, map[string]string{kindOverLimit: scan.SeverityWarning})
+	if err != nil {
+		return opts, err
+	}
+	if opts.severity.FailOn != "" && opts.maxLines == 0 {
+		return opts, errors.New("--fail-on requires --max-lines")
+	}
+	if opts.updateBaseline && (opts.baselinePath == "" || opts.maxLines == 0) {
+		return opts, errors.New("--update-baseline requires --baseline=FILE and --max-lines")
+	}
+
+	if opts.countMode != countLinesMode && opts.countMode != countFunctionsMode {
+		return opts, fmt.Errorf("invalid value for --count: %s (expected lines or functions)", opts.countMode)
+	}
+
+	switch opts.sortOrder {
+	case sortLinesDesc, sortLinesAsc, sortPath:
+	default:
+		return opts, fmt.Errorf("invalid value for --sort: %s (expected lines-desc, lines-asc, or path)", opts.sortOrder)
+	}
+
+	opts.format = strings.ToLower(strings.TrimSpace(opts.format))
+	switch opts.format {
+	case formatText, formatJSON, formatCSV, formatMarkdown:
+	default:
+		return opts, fmt.Errorf("invalid value for --format: %s (expected text, json, csv, or markdown)", opts.format)
+	}
+
+	switch opts.color {
+	case scan.ColorAuto, scan.ColorAlways, scan.ColorNever:
+	default:
+		return opts, fmt.Errorf("invalid value for --color: %s (expected auto, always, or never)", opts.color)
+	}
+
+	for _, glob := range include {
+		opts.filter.include = append(opts.filter.include, scan.GlobToRegexp(glob))
+	}
+	opts.filter.exclude = scan.NewExcludes(exclude)
+
+	if opts.stdinPaths && len(dirs) > 0 {
+		return opts, errors.New("--dir cannot be combined with --stdin-paths")
+	}
+	if len(dirs) == 0 {
+		dirs = stringSlice{"src"}
+	}
+	opts.dirs = dirs
+
+	if len(extensions) == 0 {
+		extensions = stringSlice{".ts"}
+	}
+	opts.extensions = make(map[string]bool, len(extensions))
+	for _, ext := range extensions {
+		// Ensure dot prefix
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		opts.extensions[strings.ToLower(ext)] = true
+	}
+
+	return opts, nil
 }
 
 func parsePositiveInt(value string) (int, error) {
@@ -105,58 +611,366 @@ func parsePositiveInt(value string) (int, error) {
 	return parsed, nil
 }
 
-func collectTsFiles(root string) ([]string, error) {
+// collectDirFiles walks every --dir, exiting on a missing directory, and
+// returns the files found plus each dir as a slash path relative to projectRoot.
+// Overlapping --dir values do not count a file twice.
+func collectDirFiles(opts options, projectRoot string) ([]string, []string) {
 	var files []string
-	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
+	seen := make(map[string]bool)
+	roots := make([]string, 0, len(opts.dirs))
+	for _, dir := range opts.dirs {
+		absDir := dir
+		if !filepath.IsAbs(absDir) {
+			absDir = filepath.Join(projectRoot, dir)
 		}
-		if d.IsDir() {
-			return nil
+		stat, err := os.Stat(absDir)
+		if err != nil || !stat.IsDir() {
+			fmt.Fprintf(os.Stderr, "Directory not found: %s\n", absDir)
+			os.Exit(exitError)
 		}
-		if strings.HasSuffix(strings.ToLower(path), ".ts") {
-			files = append(files, path)
+		rel, _ := filepath.Rel(projectRoot, absDir)
+		roots = append(roots, filepath.ToSlash(rel))
+
+		dirFiles, err := scan.WalkSourceFiles([]string{absDir}, opts.extensions, opts.filter.exclude, opts.useGitignore)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to collect source files: %v\n", err)
+			os.Exit(exitError)
 		}
-		return nil
-	})
-	return files, err
+		// The walk has already applied --exclude
+		for _, path := range dirFiles {
+			rel, _ := filepath.Rel(projectRoot, path)
+			if !seen[path] && opts.filter.includes(filepath.ToSlash(rel)) {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	return files, roots
 }
 
-func countLinesForFiles(paths []string, projectRoot string) ([]fileCount, error) {
-	results := make([]fileCount, 0, len(paths))
-	for _, path := range paths {
-		lines, err := countLines(path)
+// readPathList reads newline-delimited file paths (relative to projectRoot or
+// absolute) for --stdin-paths, keeping the existing files that pass the same
+// extension, excluded-directory, and glob filters as a directory walk. Paths
+// that no longer exist, such as deletions from git diff --name-only, are
+// skipped with a note on stderr.
+func readPathList(r io.Reader, projectRoot string, extensions map[string]bool, filter pathFilter) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		path := strings.TrimSpace(scanner.Text())
+		if path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+		path = filepath.Clean(path)
+		if seen[path] || !extensions[strings.ToLower(filepath.Ext(path))] {
+			continue
+		}
+		seen[path] = true
+
+		rel, err := filepath.Rel(projectRoot, path)
 		if err != nil {
 			return nil, err
 		}
+		rel = filepath.ToSlash(rel)
+		if !filter.allows(rel) {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			fmt.Fprintf(os.Stderr, "Skipping missing file: %s\n", rel)
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, scanner.Err()
+}
+
+// gitChangedFiles returns the files changed between base and HEAD, or with
+// staged the files staged for commit, as slash paths relative to projectRoot
+func gitChangedFiles(projectRoot, base string, staged bool) (map[string]bool, error) {
+	revision := base + "...HEAD"
+	if staged {
+		revision = "--cached"
+	}
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", revision, "--")
+	cmd.Dir = projectRoot
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s: %s", revision, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	files := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files[filepath.ToSlash(line)] = true
+		}
+	}
+	return files, nil
+}
+
+// countSettings controls what countLinesForFiles measures in each file
+type countSettings struct {
+	declarations       bool
+	trimTrailingBlanks bool
+}
+
+// countLinesForFiles counts paths with a bounded worker pool. Results keep
+// the order of paths; the first error in that order is returned.
+func countLinesForFiles(paths []string, projectRoot string, workerCount int, settings countSettings) ([]fileCount, error) {
+	return scan.Parallel(paths, workerCount, func(path string) (fileCount, error) {
+		lines, breakdown, err := countLines(path, settings.trimTrailingBlanks)
+		if err != nil {
+			return fileCount{}, err
+		}
+		declarations := 0
+		if settings.declarations {
+			if declarations, err = countDeclarations(path); err != nil {
+				return fileCount{}, err
+			}
+		}
 		rel, err := filepath.Rel(projectRoot, path)
 		if err != nil {
 			rel = path
 		}
-		results = append(results, fileCount{
-			path:  filepath.ToSlash(rel),
-			lines: lines,
-		})
-	}
-	return results, nil
+		return fileCount{
+			path:          filepath.ToSlash(rel),
+			lines:         lines,
+			lineBreakdown: breakdown,
+			declarations:  declarations,
+		}, nil
+	})
 }
 
-func countLines(path string) (int, error) {
+func countLines(path string, trimTrailingBlanks bool) (int, lineBreakdown, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return 0, err
+		return 0, lineBreakdown{}, err
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return countReaderLines(file, hashCommentExtensions[strings.ToLower(filepath.Ext(path))], trimTrailingBlanks)
+}
+
+// countReaderLines counts and classifies the lines read from r, split by
+// scanSourceLines so the total matches the last line number an editor shows
+// for any file that ends with a line ending. With trimTrailingBlanks, the run
+// of whitespace-only lines at the end of the input is left out.
+func countReaderLines(r io.Reader, hashComments, trimTrailingBlanks bool) (int, lineBreakdown, error) {
+	var breakdown lineBreakdown
+
+	classifier := lineClassifier{hashComments: hashComments}
+	scanner := newSourceScanner(r)
 	count := 0
+	trailingBlanks := 0
 	for scanner.Scan() {
 		count++
+		trailingBlanks++
+		switch classifier.classify(scanner.Text()) {
+		case lineCode:
+			breakdown.code++
+			trailingBlanks = 0
+		case lineComment:
+			breakdown.comment++
+			trailingBlanks = 0
+		default:
+			breakdown.blank++
+		}
 	}
 	if err := scanner.Err(); err != nil {
+		return 0, breakdown, err
+	}
+	if trimTrailingBlanks {
+		count -= trailingBlanks
+		breakdown.blank -= trailingBlanks
+	}
+	return count, breakdown, nil
+}
+
+// countDeclarations tallies function, arrow-function, method, and class
+// declarations on the code lines of a file using regex heuristics
+func countDeclarations(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
 		return 0, err
 	}
-	return count, nil
+	defer file.Close()
+
+	classifier := lineClassifier{hashComments: hashCommentExtensions[strings.ToLower(filepath.Ext(path))]}
+	scanner := newSourceScanner(file)
+	count := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if classifier.classify(line) != lineCode {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		count += len(functionKeywordRegex.FindAllStringIndex(trimmed, -1))
+		count += len(classRegex.FindAllStringIndex(trimmed, -1))
+		if arrowAssignRegex.MatchString(trimmed) {
+			count++
+		} else if methodRegex.MatchString(trimmed) && !notMethodRegex.MatchString(trimmed) {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
+
+// maxLineLength bounds a single line; bundled or minified sources can far
+// exceed bufio.Scanner's 64 KiB default
+const maxLineLength = 16 * 1024 * 1024
+
+func newSourceScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	scanner.Split(scanSourceLines)
+	return scanner
+}
+
+// scanSourceLines is a bufio.SplitFunc that ends a line at "\n", "\r\n", or
+// a lone "\r", so LF, CRLF, and old Mac files count the same. A final
+// segment without a line ending still counts as a line, while a trailing
+// line ending does not start an extra empty one: "a\nb" and "a\nb\n" are
+// both two lines.
+func scanSourceLines(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		// A "\r" at the end of the buffer may be the first half of "\r\n"
+		if !atEOF {
+			return 0, nil, nil
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+type lineKind int
+
+const (
+	lineBlank lineKind = iota
+	lineComment
+	lineCode
+)
+
+// lineClassifier tracks open `/* */` comments across lines
+type lineClassifier struct {
+	hashComments bool
+	inBlock      bool
+}
+
+func (c *lineClassifier) classify(line string) lineKind {
+	rest := strings.TrimSpace(line)
+	if rest == "" {
+		return lineBlank
+	}
+	if c.hashComments {
+		if strings.HasPrefix(rest, "#") {
+			return lineComment
+		}
+		return lineCode
+	}
+
+	sawComment := false
+	for rest != "" {
+		if c.inBlock {
+			sawComment = true
+			end := strings.Index(rest, "*/")
+			if end == -1 {
+				return lineComment
+			}
+			c.inBlock = false
+			rest = strings.TrimSpace(rest[end+2:])
+			continue
+		}
+		if strings.HasPrefix(rest, "//") {
+			return lineComment
+		}
+		if strings.HasPrefix(rest, "/*") {
+			c.inBlock = true
+			sawComment = true
+			rest = rest[2:]
+			continue
+		}
+		// Anything else is code; a trailing block comment may still open here
+		if idx := strings.LastIndex(rest, "/*"); idx != -1 && !strings.Contains(rest[idx:], "*/") {
+			c.inBlock = true
+		}
+		return lineCode
+	}
+	if sawComment {
+		return lineComment
+	}
+	return lineBlank
+}
+
+// sortCounts orders the files for output by line count, or by declarations
+// with --count=functions; equal values fall back to path
+func sortCounts(counts []fileCount, order string, byDeclarations bool) {
+	value := func(c fileCount) int {
+		if byDeclarations {
+			return c.declarations
+		}
+		return c.lines
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := value(counts[i]), value(counts[j])
+		switch {
+		case order == sortPath || a == b:
+			return counts[i].path < counts[j].path
+		case order == sortLinesAsc:
+			return a < b
+		default:
+			return a > b
+		}
+	})
+}
+
+// checkMaxLines reports every file over maxLines on stderr and returns how
+// many there are. A file in the baseline is tolerated until it grows past its
+// baselined line count.
+func checkMaxLines(counts []fileCount, maxLines int, baseline scan.Baseline) int {
+	over := 0
+	baselined := 0
+	for _, c := range counts {
+		if c.lines <= maxLines {
+			continue
+		}
+		known, inBaseline := baseline.Get(c.path, baselineKey)
+		switch {
+		case inBaseline && c.lines <= known:
+			baselined++
+			continue
+		case inBaseline:
+			fmt.Fprintf(os.Stderr, "%s exceeds %d lines (%d, up from %d in the baseline)\n", c.path, maxLines, c.lines, known)
+		default:
+			fmt.Fprintf(os.Stderr, "%s exceeds %d lines (%d)\n", c.path, maxLines, c.lines)
+		}
+		over++
+	}
+	if baselined > 0 {
+		fmt.Fprintf(os.Stderr, "Baseline: %d known large files suppressed\n", baselined)
+	}
+	return over
 }
 
 func filterByMinLines(counts []fileCount, minLines int) []fileCount {
@@ -172,26 +986,493 @@ func filterByMinLines(counts []fileCount, minLines int) []fileCount {
 	return filtered
 }
 
-func printTable(counts []fileCount) {
-	maxFileLen := len("File")
-	maxLinesLen := len("Lines")
+// reportColumns selects the optional numeric columns every format prints
+type reportColumns struct {
+	breakdown    bool
+	declarations bool
+	// shareOf adds a "% of Total" column relative to this many lines; 0 omits it
+	shareOf int
+}
+
+func (rc reportColumns) headers() []string {
+	headers := []string{"Lines"}
+	if rc.shareOf > 0 {
+		headers = append(headers, "% of Total")
+	}
+	if rc.breakdown {
+		headers = append(headers, "Code", "Comment", "Blank")
+	}
+	if rc.declarations {
+		headers = append(headers, "Declarations")
+	}
+	return headers
+}
+
+func (rc reportColumns) values(c fileCount) []string {
+	cells := []string{strconv.Itoa(c.lines)}
+	if rc.shareOf > 0 {
+		cells = append(cells, formatShare(c.lines, rc.shareOf))
+	}
+	if rc.breakdown {
+		cells = append(cells, strconv.Itoa(c.code), strconv.Itoa(c.comment), strconv.Itoa(c.blank))
+	}
+	if rc.declarations {
+		cells = append(cells, strconv.Itoa(c.declarations))
+	}
+	return cells
+}
 
+// sumCounts returns the totals row for the given files
+func sumCounts(counts []fileCount) fileCount {
+	total := fileCount{path: fmt.Sprintf("Total (%d files)", len(counts))}
 	for _, c := range counts {
+		total.lines += c.lines
+		total.code += c.code
+		total.comment += c.comment
+		total.blank += c.blank
+		total.declarations += c.declarations
+	}
+	return total
+}
+
+func printTable(w io.Writer, counts []fileCount, cols reportColumns) {
+	headers := cols.headers()
+
+	// The totals row sums only the files shown, so it respects --min-lines
+	total := sumCounts(counts)
+	rows := append(counts[:len(counts):len(counts)], total)
+
+	maxFileLen := len("File")
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = len(h)
+	}
+	for _, c := range rows {
 		if len(c.path) > maxFileLen {
 			maxFileLen = len(c.path)
 		}
-		lineLen := len(strconv.Itoa(c.lines))
-		if lineLen > maxLinesLen {
-			maxLinesLen = lineLen
+		for i, v := range cols.values(c) {
+			if len(v) > widths[i] {
+				widths[i] = len(v)
+			}
+		}
+	}
+
+	formatRow := func(file string, cells []string) string {
+		row := padRight(file, maxFileLen)
+		for i, cell := range cells {
+			row += "  " + padLeft(cell, widths[i])
+		}
+		return row
+	}
+	printRow := func(file string, cells []string) {
+		fmt.Fprintln(w, formatRow(file, cells))
+	}
+	printSeparator := func() {
+		dashes := make([]string, len(widths))
+		for i, w := range widths {
+			dashes[i] = strings.Repeat("-", w)
+		}
+		printRow(strings.Repeat("-", maxFileLen), dashes)
+	}
+
+	fmt.Fprintln(w, scan.Colorize(formatRow("File", headers), scan.AnsiBold))
+	printSeparator()
+	for _, c := range counts {
+		printRow(c.path, cols.values(c))
+	}
+	printSeparator()
+	fmt.Fprintln(w, scan.Colorize(formatRow(total.path, cols.values(total)), scan.AnsiBold))
+}
+
+// countByDir sums lines per first path segment below each scan root (e.g.
+// src/main); files directly in a root are grouped under the root itself.
+// Largest first.
+func countByDir(counts []fileCount, roots []string) []dirCount {
+	byDir := make(map[string]*dirCount)
+	var order []string
+	for _, c := range counts {
+		dir := dirBucket(c.path, roots)
+		dc, ok := byDir[dir]
+		if !ok {
+			dc = &dirCount{Dir: dir}
+			byDir[dir] = dc
+			order = append(order, dir)
+		}
+		dc.Files++
+		dc.Lines += c.lines
+	}
+
+	result := make([]dirCount, 0, len(order))
+	for _, dir := range order {
+		result = append(result, *byDir[dir])
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Lines == result[j].Lines {
+			return result[i].Dir < result[j].Dir
+		}
+		return result[i].Lines > result[j].Lines
+	})
+	return result
+}
+
+// dirBucket maps a file to the top-level directory beneath the most specific
+// scan root containing it; files directly in a root are grouped under the root
+func dirBucket(path string, roots []string) string {
+	best := ""
+	for _, root := range roots {
+		if root == "." {
+			if best == "" {
+				best = root
+			}
+			continue
+		}
+		if strings.HasPrefix(path, root+"/") && len(root) > len(best) {
+			best = root
+		}
+	}
+
+	rest := path
+	prefix := ""
+	if best != "" && best != "." {
+		rest = strings.TrimPrefix(path, best+"/")
+		prefix = best + "/"
+	}
+	if idx := strings.Index(rest, "/"); idx != -1 {
+		return prefix + rest[:idx]
+	}
+	if best == "" {
+		return "."
+	}
+	return best
+}
+
+// printDirTable writes the --by-dir summary
+func printDirTable(w io.Writer, dirs []dirCount) {
+	maxDirLen := len("Directory")
+	maxFilesLen := len("Files")
+	maxLinesLen := len("Lines")
+	for _, d := range dirs {
+		maxDirLen = max(maxDirLen, len(d.Dir))
+		maxFilesLen = max(maxFilesLen, len(strconv.Itoa(d.Files)))
+		maxLinesLen = max(maxLinesLen, len(strconv.Itoa(d.Lines)))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, scan.Colorize(fmt.Sprintf("%s  %s  %s", padRight("Directory", maxDirLen), padLeft("Files", maxFilesLen), padLeft("Lines", maxLinesLen)), scan.AnsiBold))
+	fmt.Fprintf(w, "%s  %s  %s\n", strings.Repeat("-", maxDirLen), strings.Repeat("-", maxFilesLen), strings.Repeat("-", maxLinesLen))
+	for _, d := range dirs {
+		fmt.Fprintf(w, "%s  %s  %s\n", padRight(d.Dir, maxDirLen), padLeft(strconv.Itoa(d.Files), maxFilesLen), padLeft(strconv.Itoa(d.Lines), maxLinesLen))
+	}
+}
+
+// buildHistogram buckets the files by line count; every bucket is returned,
+// including empty ones, so runs are easy to compare
+func buildHistogram(counts []fileCount) []histogramBucket {
+	buckets := make([]histogramBucket, len(histogramBounds))
+	for i, lower := range histogramBounds {
+		buckets[i].Min = lower
+		if i+1 < len(histogramBounds) {
+			buckets[i].Max = histogramBounds[i+1]
+			buckets[i].Label = fmt.Sprintf("%d-%d", lower, histogramBounds[i+1]-1)
+		} else {
+			buckets[i].Label = fmt.Sprintf("%d+", lower)
+		}
+	}
+	for _, c := range counts {
+		idx := sort.Search(len(histogramBounds), func(i int) bool { return histogramBounds[i] > c.lines }) - 1
+		buckets[idx].Files++
+	}
+	return buckets
+}
+
+// formatShare renders part/total as a percentage with one decimal place
+func formatShare(part, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// histogramBarWidth is the length of the bar drawn for the fullest bucket
+const histogramBarWidth = 40
+
+func printHistogram(w io.Writer, buckets []histogramBucket, totalFiles int) {
+	maxLabelLen := len("Lines")
+	maxFilesLen := len("Files")
+	maxShareLen := len("Share")
+	peak := 0
+	for _, b := range buckets {
+		maxLabelLen = max(maxLabelLen, len(b.Label))
+		maxFilesLen = max(maxFilesLen, len(strconv.Itoa(b.Files)))
+		maxShareLen = max(maxShareLen, len(formatShare(b.Files, totalFiles)))
+		peak = max(peak, b.Files)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, scan.Colorize(fmt.Sprintf("%s  %s  %s", padRight("Lines", maxLabelLen), padLeft("Files", maxFilesLen), padLeft("Share", maxShareLen)), scan.AnsiBold))
+	fmt.Fprintf(w, "%s  %s  %s\n", strings.Repeat("-", maxLabelLen), strings.Repeat("-", maxFilesLen), strings.Repeat("-", maxShareLen))
+	for _, b := range buckets {
+		bar := ""
+		if b.Files > 0 {
+			// Round to the nearest cell but keep any non-empty bucket visible
+			bar = strings.Repeat("#", max(1, (b.Files*histogramBarWidth+peak/2)/peak))
+		}
+		fmt.Fprintf(w, "%s  %s  %s  %s\n", padRight(b.Label, maxLabelLen), padLeft(strconv.Itoa(b.Files), maxFilesLen), padLeft(formatShare(b.Files, totalFiles), maxShareLen), scan.Colorize(bar, scan.AnsiCyan))
+	}
+}
+
+// printJSON writes the shown files and their totals as an indented JSON document
+func printJSON(w io.Writer, counts []fileCount, dirs []dirCount, histogram []histogramBucket, cols reportColumns) error {
+	report := jsonReport{
+		Files:      make([]jsonFile, 0, len(counts)),
+		TotalFiles: len(counts),
+		Dirs:       dirs,
+		Histogram:  histogram,
+	}
+	for _, c := range counts {
+		entry := jsonFile{Path: c.path, Lines: c.lines}
+		if cols.breakdown {
+			entry.Breakdown = &jsonBreakdown{Code: c.code, Comment: c.comment, Blank: c.blank}
+		}
+		if cols.declarations {
+			entry.Declarations = intPtr(c.declarations)
+		}
+		report.Files = append(report.Files, entry)
+	}
+
+	total := sumCounts(counts)
+	report.TotalLines = total.lines
+	if cols.breakdown {
+		report.Breakdown = &jsonBreakdown{Code: total.code, Comment: total.comment, Blank: total.blank}
+	}
+	if cols.declarations {
+		report.TotalDeclarations = intPtr(total.declarations)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// printCSV writes the shown files as RFC 4180 CSV with a header row
+func printCSV(w io.Writer, counts []fileCount, cols reportColumns) error {
+	writer := csv.NewWriter(w)
+	header := []string{"file"}
+	for _, h := range cols.headers() {
+		header = append(header, strings.ToLower(h))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, c := range counts {
+		if err := writer.Write(append([]string{c.path}, cols.values(c)...)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// printMarkdown renders the shown files, their total, and the optional
+// directory and histogram summaries as GitHub-flavored Markdown tables
+func printMarkdown(w io.Writer, counts []fileCount, dirs []dirCount, histogram []histogramBucket, cols reportColumns) {
+	headers := cols.headers()
+	fmt.Fprintf(w, "| File | %s |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(w, "| --- |%s\n", strings.Repeat(" ---: |", len(headers)))
+	for _, c := range counts {
+		fmt.Fprintf(w, "| `%s` | %s |\n", escapeMarkdownCell(c.path), strings.Join(cols.values(c), " | "))
+	}
+	total := sumCounts(counts)
+	fmt.Fprintf(w, "| **%s** | %s |\n", total.path, strings.Join(cols.values(total), " | "))
+
+	if len(dirs) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Directory | Files | Lines |")
+		fmt.Fprintln(w, "| --- | ---: | ---: |")
+		for _, d := range dirs {
+			fmt.Fprintf(w, "| `%s` | %d | %d |\n", escapeMarkdownCell(d.Dir), d.Files, d.Lines)
+		}
+	}
+
+	if len(histogram) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Lines | Files | Share |")
+		fmt.Fprintln(w, "| --- | ---: | ---: |")
+		for _, b := range histogram {
+			fmt.Fprintf(w, "| %s | %d | %s |\n", b.Label, b.Files, formatShare(b.Files, len(counts)))
 		}
 	}
+}
 
-	fmt.Printf("%s  %s\n", padRight("File", maxFileLen), padLeft("Lines", maxLinesLen))
-	fmt.Printf("%s  %s\n", strings.Repeat("-", maxFileLen), strings.Repeat("-", maxLinesLen))
+// loadSnapshot reads the per-file line counts from a --format=json report
+func loadSnapshot(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report jsonReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	lines := make(map[string]int, len(report.Files))
+	for _, f := range report.Files {
+		lines[f.Path] = f.Lines
+	}
+	return lines, nil
+}
 
+// compareCounts diffs the current counts against a snapshot, keeping only
+// files that were added, deleted, or changed size, largest change first
+func compareCounts(previous map[string]int, counts []fileCount) compareReport {
+	var report compareReport
+	seen := make(map[string]bool, len(counts))
 	for _, c := range counts {
-		fmt.Printf("%s  %s\n", padRight(c.path, maxFileLen), padLeft(strconv.Itoa(c.lines), maxLinesLen))
+		seen[c.path] = true
+		report.TotalAfter += c.lines
+		before, existed := previous[c.path]
+		switch {
+		case !existed:
+			report.Files = append(report.Files, fileDelta{Path: c.path, After: c.lines, Delta: c.lines, Status: statusAdded})
+			report.Added++
+		case before != c.lines:
+			report.Files = append(report.Files, fileDelta{Path: c.path, Before: before, After: c.lines, Delta: c.lines - before, Status: statusChanged})
+			report.Changed++
+		}
+	}
+	for path, before := range previous {
+		report.TotalBefore += before
+		if !seen[path] {
+			report.Files = append(report.Files, fileDelta{Path: path, Before: before, Delta: -before, Status: statusDeleted})
+			report.Deleted++
+		}
+	}
+	report.NetChange = report.TotalAfter - report.TotalBefore
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		a, b := report.Files[i], report.Files[j]
+		if abs(a.Delta) != abs(b.Delta) {
+			return abs(a.Delta) > abs(b.Delta)
+		}
+		return a.Path < b.Path
+	})
+	if report.Files == nil {
+		report.Files = []fileDelta{}
+	}
+	return report
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// formatDelta renders a line change with an explicit sign, e.g. +340 or -12
+func formatDelta(delta int) string {
+	if delta > 0 {
+		return "+" + strconv.Itoa(delta)
+	}
+	return strconv.Itoa(delta)
+}
+
+// compareCells returns the Before and After cells, using "-" for the side
+// where the file does not exist
+func compareCells(d fileDelta) (string, string) {
+	before, after := strconv.Itoa(d.Before), strconv.Itoa(d.After)
+	switch d.Status {
+	case statusAdded:
+		before = "-"
+	case statusDeleted:
+		after = "-"
 	}
+	return before, after
+}
+
+func compareSummary(report compareReport) string {
+	return fmt.Sprintf("Net change vs %s: %s lines (%d -> %d; %d changed, %d new, %d deleted)",
+		report.Snapshot, formatDelta(report.NetChange), report.TotalBefore, report.TotalAfter,
+		report.Changed, report.Added, report.Deleted)
+}
+
+func printCompareTable(w io.Writer, report compareReport) {
+	if len(report.Files) == 0 {
+		fmt.Fprintf(w, "No line count changes vs %s\n", report.Snapshot)
+		return
+	}
+
+	headers := []string{"Before", "After", "Delta"}
+	widths := []int{len(headers[0]), len(headers[1]), len(headers[2])}
+	maxFileLen := len("File")
+	for _, d := range report.Files {
+		before, after := compareCells(d)
+		maxFileLen = max(maxFileLen, len(d.Path))
+		widths[0] = max(widths[0], len(before))
+		widths[1] = max(widths[1], len(after))
+		widths[2] = max(widths[2], len(formatDelta(d.Delta)))
+	}
+
+	fmt.Fprintln(w, scan.Colorize(fmt.Sprintf("%s  %s  %s  %s", padRight("File", maxFileLen), padLeft(headers[0], widths[0]), padLeft(headers[1], widths[1]), padLeft(headers[2], widths[2])), scan.AnsiBold))
+	fmt.Fprintf(w, "%s  %s  %s  %s\n", strings.Repeat("-", maxFileLen), strings.Repeat("-", widths[0]), strings.Repeat("-", widths[1]), strings.Repeat("-", widths[2]))
+	for _, d := range report.Files {
+		before, after := compareCells(d)
+		row := fmt.Sprintf("%s  %s  %s  %s", padRight(d.Path, maxFileLen), padLeft(before, widths[0]), padLeft(after, widths[1]), padLeft(formatDelta(d.Delta), widths[2]))
+		// Flag files that appeared or disappeared since the snapshot
+		switch d.Status {
+		case statusAdded:
+			row += "  " + scan.Colorize("(new)", scan.AnsiYellow)
+		case statusDeleted:
+			row += "  " + scan.Colorize("(deleted)", scan.AnsiYellow)
+		}
+		fmt.Fprintln(w, row)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, compareSummary(report))
+}
+
+func printCompareJSON(w io.Writer, report compareReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func printCompareCSV(w io.Writer, report compareReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"file", "before", "after", "delta", "status"}); err != nil {
+		return err
+	}
+	for _, d := range report.Files {
+		before, after := compareCells(d)
+		if err := writer.Write([]string{d.Path, before, after, formatDelta(d.Delta), d.Status}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func printCompareMarkdown(w io.Writer, report compareReport) {
+	fmt.Fprintln(w, compareSummary(report))
+	if len(report.Files) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| File | Before | After | Delta | Status |")
+	fmt.Fprintln(w, "| --- | ---: | ---: | ---: | --- |")
+	for _, d := range report.Files {
+		before, after := compareCells(d)
+		fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n", escapeMarkdownCell(d.Path), before, after, formatDelta(d.Delta), d.Status)
+	}
+}
+
+// escapeMarkdownCell keeps pipes from splitting a table cell
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
 }
 
 func padRight(value string, width int) string {
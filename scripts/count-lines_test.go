@@ -0,0 +1,209 @@
+//go:build ignore
+
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
+)
+
+// Run with: go test scripts/count-lines.go scripts/count-lines_test.go
+
+func TestCountReaderLines(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "empty file", content: "", want: 0},
+		{name: "single newline", content: "\n", want: 1},
+		{name: "LF", content: "a\nb\nc\n", want: 3},
+		{name: "LF without final newline", content: "a\nb\nc", want: 3},
+		{name: "CRLF", content: "a\r\nb\r\nc\r\n", want: 3},
+		{name: "CRLF without final newline", content: "a\r\nb\r\nc", want: 3},
+		{name: "lone CR", content: "a\rb\rc\r", want: 3},
+		{name: "mixed endings", content: "a\nb\r\nc\rd", want: 4},
+		{name: "trailing blank lines", content: "a\n\n\n", want: 3},
+		{name: "trailing blank CRLF lines", content: "a\r\n\r\n\r\n", want: 3},
+		{name: "whitespace-only final segment", content: "a\n  ", want: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, err := countReaderLines(strings.NewReader(tc.content), false, false)
+			if err != nil {
+				t.Fatalf("countReaderLines(%q) returned error: %v", tc.content, err)
+			}
+			if got != tc.want {
+				t.Errorf("countReaderLines(%q) = %d, want %d", tc.content, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCountReaderLinesSplitCRLF(t *testing.T) {
+	// Reading one byte at a time separates every "\r" from its "\n"
+	content := "a\r\nb\r\n\r\nc"
+	got, _, err := countReaderLines(iotest.OneByteReader(strings.NewReader(content)), false, false)
+	if err != nil {
+		t.Fatalf("countReaderLines returned error: %v", err)
+	}
+	if got != 4 {
+		t.Errorf("countReaderLines(%q) = %d, want 4", content, got)
+	}
+}
+
+func TestCountReaderLinesBreakdown(t *testing.T) {
+	lf := "// header\n\nconst a = 1; // trailing\n/*\n * block\n */\nconst b = 2;"
+	crlf := strings.ReplaceAll(lf, "\n", "\r\n") + "\r\n"
+	want := lineBreakdown{code: 2, comment: 4, blank: 1}
+
+	for name, content := range map[string]string{"LF": lf, "CRLF": crlf} {
+		t.Run(name, func(t *testing.T) {
+			total, got, err := countReaderLines(strings.NewReader(content), false, false)
+			if err != nil {
+				t.Fatalf("countReaderLines returned error: %v", err)
+			}
+			if total != 7 {
+				t.Errorf("total = %d, want 7", total)
+			}
+			if got != want {
+				t.Errorf("breakdown = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCountReaderLinesLongLine(t *testing.T) {
+	content := strings.Repeat("x", 100*1024) + "\nshort\n"
+	got, _, err := countReaderLines(strings.NewReader(content), false, false)
+	if err != nil {
+		t.Fatalf("countReaderLines returned error: %v", err)
+	}
+	if got != 2 {
+		t.Errorf("countReaderLines = %d, want 2", got)
+	}
+}
+
+func TestBuildHistogram(t *testing.T) {
+	counts := []fileCount{
+		{path: "a.ts", lines: 0},
+		{path: "b.ts", lines: 49},
+		{path: "c.ts", lines: 50},
+		{path: "d.ts", lines: 199},
+		{path: "e.ts", lines: 200},
+		{path: "f.ts", lines: 500},
+		{path: "g.ts", lines: 4000},
+	}
+	want := []struct {
+		label string
+		files int
+	}{
+		{"0-49", 2},
+		{"50-199", 2},
+		{"200-499", 1},
+		{"500+", 2},
+	}
+
+	got := buildHistogram(counts)
+	if len(got) != len(want) {
+		t.Fatalf("buildHistogram returned %d buckets, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Label != w.label || got[i].Files != w.files {
+			t.Errorf("bucket %d = %s/%d, want %s/%d", i, got[i].Label, got[i].Files, w.label, w.files)
+		}
+	}
+}
+
+func TestPathFilter(t *testing.T) {
+	filter := pathFilter{
+		include: []*regexp.Regexp{scan.GlobToRegexp("**/*.ts")},
+		exclude: scan.NewExcludes([]string{"**/*.spec.ts", "src/generated/**", "fixtures"}),
+	}
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{"src/main/index.ts", true},
+		{"index.ts", true},
+		{"src/main/index.spec.ts", false},
+		{"src/generated/api.ts", false},
+		{"src/main/index.tsx", false},
+		{"src/fixtures/sample.ts", false},
+		{"node_modules/pkg/index.ts", false},
+	}
+
+	for _, tc := range cases {
+		if got := filter.allows(tc.path); got != tc.want {
+			t.Errorf("allows(%q) = %v, want %v", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestCountReaderLinesTrimTrailingBlanks(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "no trailing blanks", content: "a\nb\n", want: 2},
+		{name: "trailing blank lines", content: "a\nb\n\n\n", want: 2},
+		{name: "trailing whitespace lines", content: "a\nb\n  \n\t\r\n", want: 2},
+		{name: "interior blanks kept", content: "a\n\n\nb\n\n", want: 4},
+		{name: "blank file", content: "\n\n", want: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, breakdown, err := countReaderLines(strings.NewReader(tc.content), false, true)
+			if err != nil {
+				t.Fatalf("countReaderLines(%q) returned error: %v", tc.content, err)
+			}
+			if got != tc.want {
+				t.Errorf("countReaderLines(%q) = %d, want %d", tc.content, got, tc.want)
+			}
+			if sum := breakdown.code + breakdown.comment + breakdown.blank; sum != got {
+				t.Errorf("breakdown %+v sums to %d, want %d", breakdown, sum, got)
+			}
+		})
+	}
+}
+
+func TestParseArgsFailOn(t *testing.T) {
+	over := []string{kindOverLimit, kindOverLimit}
+	cases := []struct {
+		args       []string
+		wantFailed bool
+	}{
+		{[]string{"--max-lines=10", "--fail-on=warning"}, true},
+		{[]string{"--max-lines=10", "--fail-on=warning:2"}, false},
+		{[]string{"--max-lines=10", "--fail-on=error"}, false},
+		{[]string{"--max-lines=10", "--fail-on=error", "--severity=over-limit=error"}, true},
+		{[]string{"--max-lines=10"}, false},
+	}
+
+	for _, tc := range cases {
+		opts, err := parseArgs(tc.args)
+		if err != nil {
+			t.Fatalf("parseArgs(%v): %v", tc.args, err)
+		}
+		if _, failed := opts.severity.Failing(over); failed != tc.wantFailed {
+			t.Errorf("parseArgs(%v): failing = %v, want %v", tc.args, failed, tc.wantFailed)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--fail-on=warning"},
+		{"--max-lines=10", "--severity=large=error"},
+		{"--max-lines=10", "--fail-on=fatal"},
+	} {
+		if _, err := parseArgs(args); err == nil {
+			t.Errorf("parseArgs(%v) succeeded, want an error", args)
+		}
+	}
+}
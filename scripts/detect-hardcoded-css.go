@@ -1,3 +1,5 @@
+//go:build ignore
+
 package main
 
 import (
@@ -6,15 +8,17 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"sort"
 	"strings"
-	"sync"
 	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
 )
 
 // Tool metadata for usage instructions.
@@ -33,6 +37,36 @@ const (
 	matchKindNamed    = "named"
 )
 
+// Exit codes shared by every scanner
+const (
+	exitFindings = 1 // findings over a -fail-on-match / -max-allowed / -fail-on threshold
+	exitError    = 2 // invalid usage or a runtime/I/O error
+)
+
+// Values accepted by -format
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatSARIF = "sarif"
+)
+
+// sarifSchema is the schema of the SARIF 2.1.0 log written by -format sarif
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifRules describes the rule behind each match kind; the kind is the
+// rule ID of its results
+var sarifRules = []struct {
+	kind        string
+	name        string
+	description string
+}{
+	{matchKindHex, "HardcodedHexColor", "Hex color literal not routed through a theme CSS variable"},
+	{matchKindRGB, "HardcodedRGBColor", "rgb()/rgba() color not routed through a theme CSS variable"},
+	{matchKindHSL, "HardcodedHSLColor", "hsl()/hsla() color not routed through a theme CSS variable"},
+	{matchKindGradient, "HardcodedGradient", "Gradient with fixed colors instead of theme CSS variables"},
+	{matchKindNamed, "HardcodedNamedColor", "Named color literal not routed through a theme CSS variable"},
+}
+
 var (
 	hexColorPattern      = regexp.MustCompile(`#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})\b`)
 	rgbColorPattern      = regexp.MustCompile(`(?i)rgba?\([^)]*\)`)
@@ -61,13 +95,16 @@ type detectionOptions struct {
 
 type scanConfig struct {
 	root         string
-	extensions   map[string]struct{}
-	ignoreDirs   map[string]struct{}
+	extensions   map[string]bool
+	excludes     scan.Excludes
+	useGitignore bool
 	includePaths []string
 	excludePaths []string
 	workerCount  int
 	options      detectionOptions
 	whitelist    *whitelist
+	// changedFiles limits the scan to these root-relative slash paths when non-nil
+	changedFiles map[string]bool
 }
 
 type whitelistConfig struct {
@@ -100,6 +137,105 @@ type scanResult struct {
 	linesScanned int
 }
 
+// reportSummary holds the totals printed by the text summary and written as
+// the "summary" object of the JSON report
+type reportSummary struct {
+	Matches      int     `json:"matches"`
+	Files        int     `json:"files"`
+	FilesScanned int     `json:"filesScanned"`
+	LinesScanned int     `json:"linesScanned"`
+	ElapsedMs    float64 `json:"elapsedMs"`
+	Hex          int     `json:"hex"`
+	RGB          int     `json:"rgb"`
+	HSL          int     `json:"hsl"`
+	Gradient     int     `json:"gradient"`
+	Named        int     `json:"named"`
+}
+
+// jsonMatch is one color literal in the -format json report
+type jsonMatch struct {
+	Value    string `json:"value"`
+	Reason   string `json:"reason"`
+	Kind     string `json:"kind"`
+	Severity string `json:"severity"`
+}
+
+// jsonFinding is one source line in the -format json report
+type jsonFinding struct {
+	FilePath string      `json:"filePath"`
+	Line     int         `json:"line"`
+	Text     string      `json:"text"`
+	Matches  []jsonMatch `json:"matches"`
+}
+
+// jsonReport is the document written by -format json
+type jsonReport struct {
+	Summary  reportSummary `json:"summary"`
+	Findings []jsonFinding `json:"findings"`
+}
+
+// sarifLog and the types below are the subset of SARIF 2.1.0 written by
+// -format sarif, enough for GitHub code scanning
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
 type fileTask struct {
 	path        string
 	displayPath string
@@ -110,34 +246,68 @@ type fileAnalysis struct {
 	linesScanned int
 }
 
-type fileAnalysisResult struct {
-	analysis fileAnalysis
-	err      error
-}
-
 func main() {
 	root := flag.String("root", ".", "workspace path to scan")
 	extensions := flag.String("ext", ".css,.scss,.less,.ts,.tsx,.js,.jsx,.cts,.mts,.cjs,.mjs,.html", "comma-separated extensions to include")
-	ignoreList := flag.String("ignore", "node_modules,.git,dist,build,out,.next,.turbo,coverage,docs/assets", "comma-separated directories to skip entirely")
+	excludeList := flag.String("exclude", "", "comma-separated globs of files to skip, e.g. **/*.spec.ts; a bare name such as fixtures skips directories with that name, in addition to node_modules, dist, and the other defaults")
+	ignoreList := flag.String("ignore", "", "alias for -exclude")
+	useGitignore := flag.Bool("use-gitignore", false, "skip files ignored by git (.gitignore, .git/info/exclude, global excludes)")
 	includePathsFlag := flag.String("path-include", "", "comma-separated substrings; only files whose relative path contains one of these are scanned")
 	excludePathsFlag := flag.String("path-exclude", "", "comma-separated substrings; files whose relative path contains any of these are skipped")
 	matchTypesFlag := flag.String("match-types", "", "comma-separated match kinds to include (hex,rgb,hsl,gradient,named)")
 	lineContainsFlag := flag.String("line-contains", "", "only report matches whose source line contains this case-insensitive substring")
 	workersFlag := flag.Int("workers", runtime.NumCPU(), "number of worker goroutines to run in parallel")
 	summaryOnly := flag.Bool("summary", false, "only print summary statistics instead of every finding")
+	format := flag.String("format", formatText, "output format: text, json, or sarif (json and sarif go to stdout, progress and warnings to stderr; -summary does not apply to sarif)")
 	showDescription := flag.Bool("help-details", false, "print extended description")
 	whitelistPath := flag.String("whitelist", "scripts/css-scanner-whitelist.json", "path to whitelist config file")
+	changed := flag.Bool("changed", false, "only scan files changed on this branch (git diff --name-only <base>...HEAD)")
+	changedBase := flag.String("changed-base", "origin/main", "base ref used by -changed")
+	staged := flag.Bool("staged", false, "only scan files staged for commit (git diff --cached --name-only), e.g. from a pre-commit hook")
+	baselinePath := flag.String("baseline", "", "baseline JSON of known color literals keyed by file and kind:value (e.g. css-baseline.json); only new ones are reported")
+	updateBaseline := flag.Bool("update-baseline", false, "rewrite the -baseline file with the current color literals and exit")
+	colorMode := flag.String("color", scan.ColorAuto, "color the findings: auto (terminal without NO_COLOR), always, or never")
+	failOnMatch := flag.Bool("fail-on-match", false, "exit with code 1 when any color literal is reported after whitelist and baseline filtering")
+	maxAllowed := flag.Int("max-allowed", -1, "exit with code 1 when more than N color literals are reported after whitelist and baseline filtering (-1 disables the check)")
+	flag.IntVar(maxAllowed, "max", -1, "alias for -max-allowed")
+	severityFlag := flag.String("severity", "", "severity per match kind as kind=error|warning|info, e.g. hex=error,named=info (default warning)")
+	failOn := flag.String("fail-on", "", "exit with code 1 when more than N matches (default 0) are at LEVEL severity or above, as LEVEL[:N]")
+	showStats := flag.Bool("stats", false, "print a footer with files scanned, lines inspected, matches found, and elapsed time")
+	quiet := flag.Bool("quiet", false, "suppress the start banner and the -stats footer")
 
 	flag.Parse()
 
+	// Keep stdout clean for the JSON report
+	var logOut io.Writer = os.Stdout
+	switch *format {
+	case formatText:
+	case formatJSON, formatSARIF:
+		logOut = os.Stderr
+	default:
+		fmt.Fprintf(os.Stderr, "invalid -format value %q (expected text, json, or sarif)\n", *format)
+		os.Exit(exitError)
+	}
+
 	if *showDescription {
-		fmt.Print(strings.TrimSpace(description) + "\n\n")
+		fmt.Fprint(logOut, strings.TrimSpace(description)+"\n\n")
 	}
 
 	absRoot, err := filepath.Abs(*root)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to resolve root path: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+
+	enabled, err := scan.ColorEnabled(*colorMode, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	scan.SetColor(enabled && *format == formatText)
+
+	if *updateBaseline && *baselinePath == "" {
+		fmt.Fprintln(os.Stderr, "-update-baseline requires -baseline=FILE")
+		os.Exit(exitError)
 	}
 
 	workerCount := *workersFlag
@@ -148,11 +318,23 @@ func main() {
 	matchKinds, err := parseMatchTypes(*matchTypesFlag)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "invalid match types: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
 	lineContains := strings.ToLower(strings.TrimSpace(*lineContainsFlag))
 
+	policy, err := scan.NewSeverityPolicy(*severityFlag, *failOn, map[string]string{
+		matchKindHex:      scan.SeverityWarning,
+		matchKindRGB:      scan.SeverityWarning,
+		matchKindHSL:      scan.SeverityWarning,
+		matchKindGradient: scan.SeverityWarning,
+		matchKindNamed:    scan.SeverityWarning,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
 	// Load whitelist if exists
 	wl, err := loadWhitelist(*whitelistPath)
 	if err != nil {
@@ -161,8 +343,9 @@ func main() {
 
 	cfg := scanConfig{
 		root:         absRoot,
-		extensions:   buildSet(*extensions, ","),
-		ignoreDirs:   buildSet(*ignoreList, ","),
+		extensions:   make(map[string]bool),
+		excludes:     scan.NewExcludes(strings.Split(*excludeList+","+*ignoreList, ",")),
+		useGitignore: *useGitignore,
 		includePaths: buildList(*includePathsFlag, ","),
 		excludePaths: buildList(*excludePathsFlag, ","),
 		workerCount:  workerCount,
@@ -173,17 +356,104 @@ func main() {
 		},
 	}
 
-	fmt.Printf("Starting hard-coded CSS scan in %s with %d workers...\n", absRoot, workerCount)
+	for ext := range buildSet(*extensions, ",") {
+		cfg.extensions[ext] = true
+	}
+
+	if *changed || *staged {
+		cfg.changedFiles, err = gitChangedFiles(absRoot, *changedBase, *staged)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to list changed files: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+
+	if !*quiet {
+		fmt.Fprintf(logOut, "Starting hard-coded CSS scan in %s with %d workers...\n", absRoot, workerCount)
+	}
 	start := time.Now()
 
 	result, err := scanWorkspace(cfg)
 	elapsed := time.Since(start)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "scan error: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
-	printResults(result, elapsed, *summaryOnly)
+	if *updateBaseline {
+		baseline := scan.NewBaseline()
+		total := 0
+		for _, f := range result.findings {
+			for _, m := range f.Matches {
+				baseline.Add(f.FilePath, baselineKey(m))
+				total++
+			}
+		}
+		if err := scan.WriteBaseline(*baselinePath, baseline); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write baseline: %v\n", err)
+			os.Exit(exitError)
+		}
+		fmt.Fprintf(logOut, "Baseline %s updated with %d color literals.\n", *baselinePath, total)
+		return
+	}
+
+	baselined := 0
+	if *baselinePath != "" {
+		baseline, err := scan.LoadBaseline(*baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read baseline: %v\n", err)
+			os.Exit(exitError)
+		}
+		result.findings, baselined = filterBaseline(result.findings, baseline)
+	}
+
+	switch *format {
+	case formatJSON:
+		if err := printJSON(result, elapsed, *summaryOnly, policy); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write JSON: %v\n", err)
+			os.Exit(exitError)
+		}
+	case formatSARIF:
+		if err := printSARIF(result, policy); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write SARIF: %v\n", err)
+			os.Exit(exitError)
+		}
+	default:
+		printResults(result, elapsed, *summaryOnly)
+	}
+	if baselined > 0 {
+		fmt.Fprintf(logOut, "Baseline: %d known color literals suppressed\n", baselined)
+	}
+
+	var findingKinds []string
+	for _, f := range result.findings {
+		for _, m := range f.Matches {
+			findingKinds = append(findingKinds, m.Kind)
+		}
+	}
+	if *showStats && !*quiet {
+		scan.PrintStats(logOut, "css", scan.Stats{
+			Files:   result.filesScanned,
+			Lines:   result.linesScanned,
+			Matches: len(findingKinds),
+			Elapsed: elapsed,
+		})
+	}
+
+	// Whitelisted literals were dropped by filterWhitelisted during the scan
+	// and baselined ones above, so the thresholds only count what is reported
+	if *failOnMatch && len(findingKinds) > 0 {
+		fmt.Fprintf(os.Stderr, "-fail-on-match: %d color literals found\n", len(findingKinds))
+		os.Exit(exitFindings)
+	}
+	if *maxAllowed >= 0 && len(findingKinds) > *maxAllowed {
+		fmt.Fprintf(os.Stderr, "-max-allowed: %d color literals exceeds the limit of %d\n", len(findingKinds), *maxAllowed)
+		os.Exit(exitFindings)
+	}
+	if count, failed := policy.Failing(findingKinds); failed {
+		fmt.Fprintf(os.Stderr, "%d color literals at %s severity or above exceeds the -fail-on maximum of %d\n", count, policy.FailOn, policy.FailMax)
+		os.Exit(exitFindings)
+	}
 }
 
 func loadWhitelist(path string) (*whitelist, error) {
@@ -253,47 +523,142 @@ func parseMatchTypes(raw string) (map[string]struct{}, error) {
 	return set, nil
 }
 
-func printResults(result scanResult, elapsed time.Duration, summaryOnly bool) {
-	totalMatches := 0
-	kindCounts := map[string]int{
-		matchKindHex:      0,
-		matchKindRGB:      0,
-		matchKindHSL:      0,
-		matchKindGradient: 0,
-		matchKindNamed:    0,
+// summarize totals the findings per kind for the text and JSON summaries
+func summarize(result scanResult, elapsed time.Duration) reportSummary {
+	summary := reportSummary{
+		FilesScanned: result.filesScanned,
+		LinesScanned: result.linesScanned,
+		ElapsedMs:    float64(elapsed.Microseconds()) / 1000,
 	}
 	uniqueFiles := make(map[string]struct{})
-
 	for _, f := range result.findings {
 		uniqueFiles[f.FilePath] = struct{}{}
 		for _, m := range f.Matches {
-			totalMatches++
-			kindCounts[m.Kind]++
+			summary.Matches++
+			switch m.Kind {
+			case matchKindHex:
+				summary.Hex++
+			case matchKindRGB:
+				summary.RGB++
+			case matchKindHSL:
+				summary.HSL++
+			case matchKindGradient:
+				summary.Gradient++
+			case matchKindNamed:
+				summary.Named++
+			}
 		}
 	}
+	summary.Files = len(uniqueFiles)
+	return summary
+}
 
-	if totalMatches == 0 {
+func printResults(result scanResult, elapsed time.Duration, summaryOnly bool) {
+	summary := summarize(result, elapsed)
+
+	if summary.Matches == 0 {
 		fmt.Printf("Scan complete in %s. Files scanned: %d; lines inspected: %d.\n", elapsed, result.filesScanned, result.linesScanned)
 		fmt.Println("No obvious hard-coded CSS color tokens were detected.")
 		return
 	}
 
 	if !summaryOnly {
-		fmt.Printf("Detected %d potential hard-coded CSS color tokens across %d files.\n\n", totalMatches, len(uniqueFiles))
+		fmt.Println(scan.Colorize(fmt.Sprintf("Detected %d potential hard-coded CSS color tokens across %d files.", summary.Matches, summary.Files), scan.AnsiBold) + "\n")
 		for _, f := range result.findings {
-			fmt.Printf("%s:%d\n", f.FilePath, f.Line)
+			fmt.Println(scan.Colorize(fmt.Sprintf("%s:%d", f.FilePath, f.Line), scan.AnsiCyan))
 			fmt.Printf("  %s\n", strings.TrimSpace(f.Text))
 			for _, m := range f.Matches {
-				fmt.Printf("    -> %s (%s)\n", m.Value, m.Reason)
+				fmt.Printf("    -> %s (%s)\n", scan.Colorize(m.Value, scan.AnsiYellow), m.Reason)
 			}
 			fmt.Println()
 		}
 	}
 
-	fmt.Printf("Summary: %d matches across %d files (scanned %d files / %d lines) in %s.\n",
-		totalMatches, len(uniqueFiles), result.filesScanned, result.linesScanned, elapsed)
+	fmt.Println(scan.Colorize(fmt.Sprintf("Summary: %d matches across %d files (scanned %d files / %d lines) in %s.",
+		summary.Matches, summary.Files, result.filesScanned, result.linesScanned, elapsed), scan.AnsiBold))
 	fmt.Printf("  hex=%d rgb=%d hsl=%d gradient=%d named=%d\n",
-		kindCounts[matchKindHex], kindCounts[matchKindRGB], kindCounts[matchKindHSL], kindCounts[matchKindGradient], kindCounts[matchKindNamed])
+		summary.Hex, summary.RGB, summary.HSL, summary.Gradient, summary.Named)
+}
+
+// printJSON writes the findings and their summary to stdout, or only the
+// summary object when summaryOnly is set. Findings keep the scan's file and
+// line order, so the document is stable between runs.
+func printJSON(result scanResult, elapsed time.Duration, summaryOnly bool, policy scan.SeverityPolicy) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	summary := summarize(result, elapsed)
+	if summaryOnly {
+		return encoder.Encode(struct {
+			Summary reportSummary `json:"summary"`
+		}{summary})
+	}
+
+	report := jsonReport{Summary: summary, Findings: make([]jsonFinding, 0, len(result.findings))}
+	for _, f := range result.findings {
+		matches := make([]jsonMatch, 0, len(f.Matches))
+		for _, m := range f.Matches {
+			matches = append(matches, jsonMatch{
+				Value:    m.Value,
+				Reason:   m.Reason,
+				Kind:     m.Kind,
+				Severity: policy.Severity(m.Kind),
+			})
+		}
+		report.Findings = append(report.Findings, jsonFinding{
+			FilePath: f.FilePath,
+			Line:     f.Line,
+			Text:     f.Text,
+			Matches:  matches,
+		})
+	}
+	return encoder.Encode(report)
+}
+
+// sarifLevel maps a severity to its SARIF result level
+func sarifLevel(severity string) string {
+	if severity == scan.SeverityInfo {
+		return "note"
+	}
+	return severity
+}
+
+// printSARIF writes the findings as one SARIF 2.1.0 run on stdout, one result
+// per color literal and one rule per match kind. The run is written even
+// without findings, so upload steps always get a valid log.
+func printSARIF(result scanResult, policy scan.SeverityPolicy) error {
+	driver := sarifDriver{Name: "detect-hardcoded-css", Rules: make([]sarifRule, 0, len(sarifRules))}
+	ruleIndex := make(map[string]int, len(sarifRules))
+	for i, rule := range sarifRules {
+		ruleIndex[rule.kind] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.kind,
+			Name:                 rule.name,
+			ShortDescription:     sarifMessage{Text: rule.description},
+			DefaultConfiguration: sarifConfiguration{Level: sarifLevel(policy.Severity(rule.kind))},
+		})
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, f := range result.findings {
+		for _, m := range f.Matches {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    m.Kind,
+				RuleIndex: ruleIndex[m.Kind],
+				Level:     sarifLevel(policy.Severity(m.Kind)),
+				Message:   sarifMessage{Text: fmt.Sprintf("Hard-coded color %s (%s)", m.Value, m.Reason)},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: f.FilePath},
+						Region:           sarifRegion{StartLine: f.Line},
+					},
+				}},
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}})
 }
 
 func scanWorkspace(cfg scanConfig) (scanResult, error) {
@@ -306,48 +671,20 @@ func scanWorkspace(cfg scanConfig) (scanResult, error) {
 		return scanResult{}, nil
 	}
 
-	jobCh := make(chan fileTask)
-	resultCh := make(chan fileAnalysisResult)
-
-	var wg sync.WaitGroup
-	for i := 0; i < cfg.workerCount; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for task := range jobCh {
-				analysis, err := analyzeFile(task, cfg.options, cfg.whitelist)
-				resultCh <- fileAnalysisResult{analysis: analysis, err: err}
-			}
-		}()
+	analyses, err := scan.Parallel(tasks, cfg.workerCount, func(task fileTask) (fileAnalysis, error) {
+		return analyzeFile(task, cfg.options, cfg.whitelist)
+	})
+	if err != nil {
+		return scanResult{}, err
 	}
 
-	go func() {
-		for _, task := range tasks {
-			jobCh <- task
-		}
-		close(jobCh)
-	}()
-
-	go func() {
-		wg.Wait()
-		close(resultCh)
-	}()
-
 	var (
 		allFindings  []finding
 		linesScanned int
-		firstErr     error
 	)
-
-	for res := range resultCh {
-		if res.err != nil && firstErr == nil {
-			firstErr = res.err
-			continue
-		}
-		linesScanned += res.analysis.linesScanned
-		if len(res.analysis.findings) > 0 {
-			allFindings = append(allFindings, res.analysis.findings...)
-		}
+	for _, analysis := range analyses {
+		linesScanned += analysis.linesScanned
+		allFindings = append(allFindings, analysis.findings...)
 	}
 
 	sort.Slice(allFindings, func(i, j int) bool {
@@ -361,47 +698,85 @@ func scanWorkspace(cfg scanConfig) (scanResult, error) {
 		findings:     allFindings,
 		filesScanned: len(tasks),
 		linesScanned: linesScanned,
-	}, firstErr
+	}, nil
 }
 
 func collectFileTasks(cfg scanConfig) ([]fileTask, error) {
-	var tasks []fileTask
-
-	err := filepath.WalkDir(cfg.root, func(path string, entry fs.DirEntry, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
+	paths, err := scan.WalkSourceFiles([]string{cfg.root}, cfg.extensions, cfg.excludes, cfg.useGitignore)
+	if err != nil {
+		return nil, err
+	}
 
+	var tasks []fileTask
+	for _, path := range paths {
 		relPath := relativePath(cfg.root, path)
-		normalizedRel := normalizeForMatch(relPath)
-
-		if entry.IsDir() {
-			if shouldIgnoreDir(entry.Name(), cfg.ignoreDirs) || matchesAny(normalizedRel, cfg.excludePaths) {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		if !shouldInspectFile(path, cfg.extensions) {
-			return nil
+		if !shouldProcessPath(normalizeForMatch(relPath), cfg.includePaths, cfg.excludePaths) {
+			continue
 		}
-
-		if !shouldProcessPath(normalizedRel, cfg.includePaths, cfg.excludePaths) {
-			return nil
+		if cfg.changedFiles != nil && !cfg.changedFiles[relPath] {
+			continue
 		}
-
 		tasks = append(tasks, fileTask{
 			path:        path,
-			displayPath: filepath.ToSlash(relPath),
+			displayPath: relPath,
 		})
+	}
+	return tasks, nil
+}
 
-		return nil
-	})
+// gitChangedFiles returns the files changed between base and HEAD, or with
+// staged the files staged for commit, as slash paths relative to root
+func gitChangedFiles(root, base string, staged bool) (map[string]bool, error) {
+	revision := base + "...HEAD"
+	if staged {
+		revision = "--cached"
+	}
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", revision, "--")
+	cmd.Dir = root
+	out, err := cmd.Output()
 	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s: %s", revision, strings.TrimSpace(string(exitErr.Stderr)))
+		}
 		return nil, err
 	}
 
-	return tasks, nil
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			changed[filepath.ToSlash(line)] = true
+		}
+	}
+	return changed, nil
+}
+
+// baselineKey identifies a match within its file for the baseline
+func baselineKey(m match) string {
+	return m.Kind + ":" + m.Value
+}
+
+// filterBaseline drops the matches covered by the baseline, and findings left
+// without matches, returning the rest and the number of matches dropped.
+// Findings must be sorted by file and line.
+func filterBaseline(findings []finding, baseline scan.Baseline) ([]finding, int) {
+	var kept []finding
+	dropped := 0
+	for _, f := range findings {
+		var matches []match
+		for _, m := range f.Matches {
+			if baseline.Take(f.FilePath, baselineKey(m)) {
+				dropped++
+				continue
+			}
+			matches = append(matches, m)
+		}
+		if len(matches) > 0 {
+			f.Matches = matches
+			kept = append(kept, f)
+		}
+	}
+	return kept, dropped
 }
 
 func analyzeFile(task fileTask, opts detectionOptions, wl *whitelist) (fileAnalysis, error) {
@@ -661,20 +1036,6 @@ func buildList(input, sep string) []string {
 	return list
 }
 
-func shouldIgnoreDir(name string, ignoreDirs map[string]struct{}) bool {
-	_, exists := ignoreDirs[strings.ToLower(name)]
-	return exists
-}
-
-func shouldInspectFile(path string, extSet map[string]struct{}) bool {
-	if len(extSet) == 0 {
-		return true
-	}
-	ext := strings.ToLower(filepath.Ext(path))
-	_, ok := extSet[ext]
-	return ok
-}
-
 func shouldSkipLine(line string) bool {
 	trimmed := strings.TrimSpace(line)
 	if trimmed == "" {
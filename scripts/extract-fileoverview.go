@@ -1,35 +1,71 @@
+//go:build ignore
+
 // extract-fileoverview.go
 //
 // Extracts @fileoverview blocks from source files under ./src and writes a Markdown report.
+// Each entry heading also shows the file's total line count.
 // 1:1 port of the original TypeScript script:
 //
 //   Default output: fileoverview-report.md
 //   Flags:
-//     --output=FILE   set output markdown path (relative to CWD)
+//     --output=FILE   set output markdown path (relative to CWD); "-" writes to stdout
+//     --stdout        alias for --output=-
 //     --lines=N       number of lines to inspect per file (default 50, must be > 0)
 //     --debug         log each file where an @fileoverview is found
+//     --template=FILE render the report through a Go text/template instead of
+//                     the built-in layout (fields: .Generated, .TotalFiles,
+//                     .FoundCount, .Entries; funcs: lines, thousands)
+//     --front-matter  prepend a YAML front-matter block (generated date, totals)
+//                     for static site generators such as Hugo
+//     --path-include=P  only report files whose project-relative path contains P
+//                       (or matches P as a glob, e.g. src/renderer/**); comma-separated
+//     --path-exclude=P  skip files whose path contains/matches P; comma-separated
+//     --git-dates     annotate each entry with its last git commit date (skipped
+//                     with a warning when git is unavailable)
+//     --coverage      append a coverage summary (documented / total) and a
+//                     per-directory breakdown to the report
+//     --watch         keep running and regenerate the report when source files
+//                     change (polls for changes, re-reads only modified files)
+//     --workers=N     number of files read in parallel (default: number of CPUs)
 //
 // Usage examples:
 //   go build -o extract-fileoverview .
 //   ./extract-fileoverview
 //   ./extract-fileoverview --output=custom.md --lines=80 --debug
+//   ./extract-fileoverview --template=docs/fileoverview.tpl.md
+//   ./extract-fileoverview --path-include='src/renderer/**' --path-exclude=__tests__
 
 package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
 )
 
 const (
 	DefaultOutput = "fileoverview-report.md"
 	DefaultLines  = 50
+	// StdoutOutput is the --output value that streams the report to stdout.
+	StdoutOutput = "-"
+	// ExitError is the exit code for a runtime/I/O error, shared by every scanner.
+	ExitError = 2
+
+	// watchPollInterval is how often --watch rescans the tree for changes;
+	// watchDebounce is how long the tree must stay quiet before regenerating.
+	watchPollInterval = 500 * time.Millisecond
+	watchDebounce     = 300 * time.Millisecond
 )
 
 // Supported source file extensions (case sensitive, same as TS version)
@@ -40,18 +76,64 @@ var supportedExtensions = map[string]struct{}{
 	".jsx": {},
 }
 
+// Directories skipped while walking, the same defaults the other scanners use
+var excludedDirs = make(map[string]bool, len(scan.DefaultExcludes))
+
+func init() {
+	for _, name := range scan.DefaultExcludes {
+		excludedDirs[name] = true
+	}
+}
+
 type FileOverviewEntry struct {
-	File     string
-	Overview string
+	File      string
+	Overview  string
+	LineCount int
+	// LastModified is the file's last commit date (YYYY-MM-DD); only set with --git-dates.
+	LastModified string
 }
 
 type ScriptOptions struct {
-	Output     string
-	CheckLines int
-	Debug      bool
+	Output      string
+	CheckLines  int
+	Debug       bool
+	Template    string
+	FrontMatter bool
+	Filter      PathFilter
+	GitDates    bool
+	Coverage    bool
+	Watch       bool
+	Workers     int
+}
+
+// PathFilter scopes the scan to project-relative paths. Each pattern is either a
+// plain substring or, when it contains glob metacharacters, a glob where `*`
+// matches within a path segment and `**` matches across segments.
+type PathFilter struct {
+	Includes []*regexp.Regexp
+	Excludes []*regexp.Regexp
 }
 
-var overviewRe = regexp.MustCompile(`(?is)/\*\*[\s\S]*?@fileoverview([\s\S]*?)\*/`)
+// DirectoryCoverage counts documented files within one top-level source directory.
+type DirectoryCoverage struct {
+	Dir        string
+	Documented int
+	Total      int
+}
+
+// ReportData is the value handed to a custom --template.
+type ReportData struct {
+	Generated  string
+	TotalFiles int
+	FoundCount int
+	Entries    []FileOverviewEntry
+	// Coverage holds the per-directory breakdown; only set with --coverage.
+	Coverage []DirectoryCoverage
+}
+
+// overviewRe captures everything after the tag up to the closing `*/`. The
+// terminator accepts extra stars (`**/`) so they don't leak into the text.
+var overviewRe = regexp.MustCompile(`(?is)/\*\*[\s\S]*?@fileoverview([\s\S]*?)\*+/`)
 var starPrefixRe = regexp.MustCompile(`^\s*\*\s?`)
 
 func parseArgs(args []string) ScriptOptions {
@@ -59,6 +141,7 @@ func parseArgs(args []string) ScriptOptions {
 		Output:     DefaultOutput,
 		CheckLines: DefaultLines,
 		Debug:      false,
+		Workers:    runtime.NumCPU(),
 	}
 
 	for _, arg := range args {
@@ -72,15 +155,113 @@ func parseArgs(args []string) ScriptOptions {
 			if n, err := strconv.Atoi(value); err == nil && n > 0 {
 				opts.CheckLines = n
 			}
+		} else if strings.HasPrefix(arg, "--workers=") {
+			value := strings.TrimPrefix(arg, "--workers=")
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				opts.Workers = n
+			}
+		} else if arg == "--stdout" {
+			opts.Output = StdoutOutput
 		} else if arg == "--debug" {
 			opts.Debug = true
+		} else if arg == "--watch" {
+			opts.Watch = true
+		} else if arg == "--coverage" {
+			opts.Coverage = true
+		} else if arg == "--git-dates" {
+			opts.GitDates = true
+		} else if arg == "--front-matter" {
+			opts.FrontMatter = true
+		} else if strings.HasPrefix(arg, "--template=") {
+			opts.Template = strings.TrimPrefix(arg, "--template=")
+		} else if strings.HasPrefix(arg, "--path-include=") {
+			opts.Filter.Includes = append(opts.Filter.Includes, parsePathPatterns(strings.TrimPrefix(arg, "--path-include="))...)
+		} else if strings.HasPrefix(arg, "--path-exclude=") {
+			opts.Filter.Excludes = append(opts.Filter.Excludes, parsePathPatterns(strings.TrimPrefix(arg, "--path-exclude="))...)
 		}
 	}
 
 	return opts
 }
 
-func collectSourceFiles(rootDir string) ([]string, error) {
+// parsePathPatterns splits a comma-separated list into compiled path patterns.
+func parsePathPatterns(raw string) []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, item := range strings.Split(raw, ",") {
+		item = filepath.ToSlash(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+		patterns = append(patterns, compilePathPattern(item))
+	}
+	return patterns
+}
+
+// compilePathPattern turns a substring or glob into a regexp over slash paths.
+func compilePathPattern(pattern string) *regexp.Regexp {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			i++
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				// "**/" matches zero or more leading directories
+				i++
+				b.WriteString("(?:.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end == -1 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString(pattern[i : i+end+1])
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		// Malformed character class; fall back to a literal substring match.
+		return regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+	return re
+}
+
+// Allows reports whether a project-relative slash path passes the filter.
+func (f PathFilter) Allows(relPath string) bool {
+	for _, re := range f.Excludes {
+		if re.MatchString(relPath) {
+			return false
+		}
+	}
+	if len(f.Includes) == 0 {
+		return true
+	}
+	for _, re := range f.Includes {
+		if re.MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func collectSourceFiles(rootDir, projectRoot string, filter PathFilter) ([]string, error) {
 	entries, err := os.ReadDir(rootDir)
 	if err != nil {
 		return nil, err
@@ -92,7 +273,10 @@ func collectSourceFiles(rootDir string) ([]string, error) {
 		fullPath := filepath.Join(rootDir, entry.Name())
 
 		if entry.IsDir() {
-			subFiles, err := collectSourceFiles(fullPath)
+			if excludedDirs[entry.Name()] {
+				continue
+			}
+			subFiles, err := collectSourceFiles(fullPath, projectRoot, filter)
 			if err != nil {
 				return nil, err
 			}
@@ -109,7 +293,15 @@ func collectSourceFiles(rootDir string) ([]string, error) {
 			continue
 		}
 
-		if _, ok := supportedExtensions[filepath.Ext(entry.Name())]; ok {
+		if _, ok := supportedExtensions[filepath.Ext(entry.Name())]; !ok {
+			continue
+		}
+
+		relativePath, err := filepath.Rel(projectRoot, fullPath)
+		if err != nil {
+			relativePath = fullPath
+		}
+		if filter.Allows(filepath.ToSlash(relativePath)) {
 			files = append(files, fullPath)
 		}
 	}
@@ -129,8 +321,10 @@ func extractOverviewBlock(snippet string) string {
 	raw = strings.ReplaceAll(raw, "\r\n", "\n")
 	lines := strings.Split(raw, "\n")
 
-	for i, line := range lines {
-		lines[i] = starPrefixRe.ReplaceAllString(line, "")
+	// The first line is the remainder of the tag line itself (the whole overview
+	// for single-line comments), so it never carries a leading "*" to strip.
+	for i := 1; i < len(lines); i++ {
+		lines[i] = starPrefixRe.ReplaceAllString(lines[i], "")
 	}
 
 	cleaned := strings.TrimSpace(strings.Join(lines, "\n"))
@@ -140,61 +334,150 @@ func extractOverviewBlock(snippet string) string {
 	return cleaned
 }
 
-func buildReportEntries(files []string, checkLines int, debug bool, projectRoot string) ([]FileOverviewEntry, error) {
-	entries := make([]FileOverviewEntry, 0)
+// readEntry reads a file's header window and extracts its overview. The bool
+// result is false when the file has no (non-empty) @fileoverview block.
+func readEntry(filePath string, checkLines int, projectRoot string) (FileOverviewEntry, bool, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return FileOverviewEntry{}, false, err
+	}
 
-	for _, filePath := range files {
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			return nil, err
-		}
+	content := string(data)
+	// Normalize newlines before splitting
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lineCount := countLines(content)
+	lines := strings.Split(content, "\n")
+	if len(lines) > checkLines {
+		lines = lines[:checkLines]
+	}
 
-		content := string(data)
-		// Normalize newlines before splitting
-		content = strings.ReplaceAll(content, "\r\n", "\n")
-		lines := strings.Split(content, "\n")
-		if len(lines) > checkLines {
-			lines = lines[:checkLines]
-		}
+	snippet := strings.Join(lines, "\n")
+	overview := extractOverviewBlock(snippet)
+	if overview == "" {
+		return FileOverviewEntry{}, false, nil
+	}
 
-		snippet := strings.Join(lines, "\n")
-		overview := extractOverviewBlock(snippet)
-		if overview == "" {
-			continue
-		}
+	relativePath, err := filepath.Rel(projectRoot, filePath)
+	if err != nil {
+		relativePath = filePath
+	}
+	relativePath = filepath.ToSlash(relativePath)
 
-		relativePath, err := filepath.Rel(projectRoot, filePath)
-		if err != nil {
-			relativePath = filePath
+	return FileOverviewEntry{
+		File:      relativePath,
+		Overview:  overview,
+		LineCount: lineCount,
+	}, true, nil
+}
+
+// buildReportEntries extracts the overview of every file that has one,
+// reading files on workerCount goroutines; entries keep the order of files.
+// Debug lines are written to logOut so they never mix with a stdout report.
+func buildReportEntries(files []string, checkLines int, debug bool, projectRoot string, workerCount int, logOut io.Writer) ([]FileOverviewEntry, error) {
+	type readResult struct {
+		entry FileOverviewEntry
+		found bool
+	}
+	results, err := scan.Parallel(files, workerCount, func(file string) (readResult, error) {
+		entry, found, err := readEntry(file, checkLines, projectRoot)
+		return readResult{entry: entry, found: found}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]FileOverviewEntry, 0)
+	for _, result := range results {
+		if !result.found {
+			continue
 		}
-		relativePath = filepath.ToSlash(relativePath)
+		entry := result.entry
 
 		if debug {
-			fmt.Printf("Found @fileoverview in: %s\n", relativePath)
+			fmt.Fprintf(logOut, "Found @fileoverview in: %s\n", entry.File)
 		}
 
-		entries = append(entries, FileOverviewEntry{
-			File:     relativePath,
-			Overview: overview,
-		})
+		entries = append(entries, entry)
 	}
 
 	return entries, nil
 }
 
-func buildMarkdown(entries []FileOverviewEntry, totalFiles int) string {
-	lines := make([]string, 0)
+// annotateGitDates fills LastModified from `git log -1 --format=%cs` for each
+// entry. If git is missing or the project isn't a repository it logs a warning
+// and leaves the entries untouched.
+func annotateGitDates(entries []FileOverviewEntry, projectRoot string, logOut io.Writer) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		fmt.Fprintln(logOut, "⚠️  git not found; skipping --git-dates")
+		return
+	}
 
-	lines = append(lines, "# Fileoverview Report")
-	lines = append(lines, "")
+	probe := exec.Command(gitPath, "rev-parse", "--is-inside-work-tree")
+	probe.Dir = projectRoot
+	if err := probe.Run(); err != nil {
+		fmt.Fprintln(logOut, "⚠️  not a git repository; skipping --git-dates")
+		return
+	}
 
-	// Match Date.toISOString() format (UTC, 3 fractional digits)
-	now := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
-	lines = append(lines, fmt.Sprintf("Generated: %s", now))
-	lines = append(lines, fmt.Sprintf("Total files scanned: %d", totalFiles))
-	lines = append(lines, fmt.Sprintf("Files with @fileoverview: %d", len(entries)))
-	lines = append(lines, "")
+	for i := range entries {
+		cmd := exec.Command(gitPath, "log", "-1", "--format=%cs", "--", entries[i].File)
+		cmd.Dir = projectRoot
+		out, err := cmd.Output()
+		if err != nil {
+			continue
+		}
+		entries[i].LastModified = strings.TrimSpace(string(out))
+	}
+}
+
+// countLines counts lines the same way count-lines does (one per line ended by
+// "\n", "\r\n", or a lone "\r", plus a final unterminated line if present).
+func countLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r", "\n")
+	count := strings.Count(content, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		count++
+	}
+	return count
+}
 
+// formatThousands renders n with comma thousands separators (1240 -> "1,240").
+func formatThousands(n int) string {
+	digits := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+func formatLineCount(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return formatThousands(n) + " lines"
+}
+
+func buildReportData(entries []FileOverviewEntry, totalFiles int) ReportData {
 	sorted := make([]FileOverviewEntry, len(entries))
 	copy(sorted, entries)
 
@@ -202,20 +485,331 @@ func buildMarkdown(entries []FileOverviewEntry, totalFiles int) string {
 		return sorted[i].File < sorted[j].File
 	})
 
-	for _, entry := range sorted {
-		lines = append(lines, fmt.Sprintf("## %s", entry.File))
+	return ReportData{
+		// Match Date.toISOString() format (UTC, 3 fractional digits)
+		Generated:  time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+		TotalFiles: totalFiles,
+		FoundCount: len(entries),
+		Entries:    sorted,
+	}
+}
+
+// buildCoverage groups every scanned file by its first directory below the
+// source root (e.g. src/main, src/renderer) and counts how many are documented.
+func buildCoverage(files []string, entries []FileOverviewEntry, projectRoot, srcDir string) []DirectoryCoverage {
+	documented := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		documented[entry.File] = true
+	}
+
+	srcRel, err := filepath.Rel(projectRoot, srcDir)
+	if err != nil {
+		srcRel = srcDir
+	}
+	srcRel = filepath.ToSlash(srcRel)
+
+	byDir := make(map[string]*DirectoryCoverage)
+	for _, filePath := range files {
+		rel, err := filepath.Rel(projectRoot, filePath)
+		if err != nil {
+			rel = filePath
+		}
+		rel = filepath.ToSlash(rel)
+
+		dir := srcRel
+		inner := strings.TrimPrefix(rel, srcRel+"/")
+		if idx := strings.Index(inner, "/"); idx != -1 {
+			dir = srcRel + "/" + inner[:idx]
+		}
+
+		cov, ok := byDir[dir]
+		if !ok {
+			cov = &DirectoryCoverage{Dir: dir}
+			byDir[dir] = cov
+		}
+		cov.Total++
+		if documented[rel] {
+			cov.Documented++
+		}
+	}
+
+	result := make([]DirectoryCoverage, 0, len(byDir))
+	for _, cov := range byDir {
+		result = append(result, *cov)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Dir < result[j].Dir
+	})
+	return result
+}
+
+func coveragePercent(documented, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(documented) / float64(total) * 100
+}
+
+func buildCoverageSection(data ReportData) []string {
+	lines := []string{
+		"## Coverage",
+		"",
+		fmt.Sprintf("Coverage: %.1f%% (%d of %d files documented)", coveragePercent(data.FoundCount, data.TotalFiles), data.FoundCount, data.TotalFiles),
+		"",
+		"| Directory | Documented | Total | Coverage |",
+		"| --- | ---: | ---: | ---: |",
+	}
+	for _, cov := range data.Coverage {
+		lines = append(lines, fmt.Sprintf("| %s | %d | %d | %.1f%% |", cov.Dir, cov.Documented, cov.Total, coveragePercent(cov.Documented, cov.Total)))
+	}
+	lines = append(lines, "")
+	return lines
+}
+
+// buildFrontMatter renders the report metadata as a YAML front-matter block.
+func buildFrontMatter(data ReportData) string {
+	lines := []string{
+		"---",
+		"title: Fileoverview Report",
+		fmt.Sprintf("generated: %q", data.Generated),
+		fmt.Sprintf("total_files: %d", data.TotalFiles),
+		fmt.Sprintf("files_with_fileoverview: %d", data.FoundCount),
+		"---",
+		"",
+	}
+	return strings.Join(lines, "\n")
+}
+
+func buildMarkdown(data ReportData, frontMatter bool) string {
+	lines := make([]string, 0)
+
+	if frontMatter {
+		// Metadata lives in the front-matter, so the plain summary lines are skipped.
+		lines = append(lines, buildFrontMatter(data))
+		lines = append(lines, "# Fileoverview Report")
+		lines = append(lines, "")
+	} else {
+		lines = append(lines, "# Fileoverview Report")
+		lines = append(lines, "")
+
+		lines = append(lines, fmt.Sprintf("Generated: %s", data.Generated))
+		lines = append(lines, fmt.Sprintf("Total files scanned: %d", data.TotalFiles))
+		lines = append(lines, fmt.Sprintf("Files with @fileoverview: %d", data.FoundCount))
+		lines = append(lines, "")
+	}
+
+	for _, entry := range data.Entries {
+		details := formatLineCount(entry.LineCount)
+		if entry.LastModified != "" {
+			details += ", last modified " + entry.LastModified
+		}
+		lines = append(lines, fmt.Sprintf("## %s (%s)", entry.File, details))
 		lines = append(lines, "")
 		lines = append(lines, entry.Overview)
 		lines = append(lines, "")
 	}
 
-	if len(sorted) == 0 {
+	if len(data.Entries) == 0 {
 		lines = append(lines, "_No @fileoverview blocks were found._")
 	}
 
+	if data.Coverage != nil {
+		if len(data.Entries) == 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, buildCoverageSection(data)...)
+	}
+
 	return strings.Join(lines, "\n")
 }
 
+// renderTemplate renders the report through a user-supplied text/template file.
+func renderTemplate(templatePath string, data ReportData) (string, error) {
+	raw, err := os.ReadFile(templatePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tpl, err := template.New(filepath.Base(templatePath)).Funcs(template.FuncMap{
+		"lines":     formatLineCount,
+		"thousands": formatThousands,
+	}).Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", templatePath, err)
+	}
+
+	var b strings.Builder
+	if err := tpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", templatePath, err)
+	}
+	return b.String(), nil
+}
+
+// writeReport renders the report for the given entries and writes it to the
+// configured destination, returning a human-readable name for that destination.
+func writeReport(opts ScriptOptions, projectRoot, srcDir string, files []string, entries []FileOverviewEntry) (string, error) {
+	data := buildReportData(entries, len(files))
+	if opts.Coverage {
+		data.Coverage = buildCoverage(files, entries, projectRoot, srcDir)
+	}
+
+	var markdown string
+	if opts.Template != "" {
+		var err error
+		markdown, err = renderTemplate(filepath.Join(projectRoot, opts.Template), data)
+		if err != nil {
+			return "", err
+		}
+		if opts.FrontMatter {
+			markdown = buildFrontMatter(data) + "\n" + markdown
+		}
+	} else {
+		markdown = buildMarkdown(data, opts.FrontMatter)
+	}
+
+	if opts.Output == StdoutOutput {
+		if _, err := io.WriteString(os.Stdout, markdown); err != nil {
+			return "", err
+		}
+		return "stdout", nil
+	}
+
+	outputPath := filepath.Join(projectRoot, opts.Output)
+	if err := os.WriteFile(outputPath, []byte(markdown), 0o644); err != nil {
+		return "", err
+	}
+	return opts.Output, nil
+}
+
+// watchedFile is the cached state of one source file in --watch mode.
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	entry   FileOverviewEntry
+	found   bool
+}
+
+// watch polls the source tree and rewrites the report once changes settle.
+// Only new or modified files are re-read; everything else comes from the cache.
+func watch(opts ScriptOptions, projectRoot, srcDir string, files []string, entries []FileOverviewEntry, logOut io.Writer) error {
+	byFile := make(map[string]FileOverviewEntry, len(entries))
+	for _, entry := range entries {
+		byFile[entry.File] = entry
+	}
+
+	// Prime the cache from the initial pass so nothing is read twice.
+	cache := make(map[string]watchedFile, len(files))
+	for _, filePath := range files {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(projectRoot, filePath)
+		if err != nil {
+			rel = filePath
+		}
+		entry, found := byFile[filepath.ToSlash(rel)]
+		cache[filePath] = watchedFile{modTime: info.ModTime(), size: info.Size(), entry: entry, found: found}
+	}
+
+	fmt.Fprintln(logOut, "👀 Watching for changes (Ctrl+C to stop)...")
+
+	var (
+		pending    bool
+		lastChange time.Time
+	)
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		current, err := collectSourceFiles(srcDir, projectRoot, opts.Filter)
+		if err != nil {
+			fmt.Fprintf(logOut, "⚠️  Failed to rescan %s: %v\n", srcDir, err)
+			continue
+		}
+
+		changed := 0
+		seen := make(map[string]bool, len(current))
+		for _, filePath := range current {
+			seen[filePath] = true
+
+			info, err := os.Stat(filePath)
+			if err != nil {
+				continue
+			}
+			if prev, ok := cache[filePath]; ok && prev.modTime.Equal(info.ModTime()) && prev.size == info.Size() {
+				continue
+			}
+
+			state, err := loadWatchedFile(filePath, opts, projectRoot)
+			if err != nil {
+				fmt.Fprintf(logOut, "⚠️  Failed to read %s: %v\n", filePath, err)
+				continue
+			}
+			cache[filePath] = state
+			changed++
+		}
+		for filePath := range cache {
+			if !seen[filePath] {
+				delete(cache, filePath)
+				changed++
+			}
+		}
+
+		if changed > 0 {
+			pending = true
+			lastChange = time.Now()
+			continue
+		}
+		if !pending || time.Since(lastChange) < watchDebounce {
+			continue
+		}
+		pending = false
+
+		start := time.Now()
+		entries := make([]FileOverviewEntry, 0, len(cache))
+		for _, state := range cache {
+			if state.found {
+				entries = append(entries, state.entry)
+			}
+		}
+
+		destination, err := writeReport(opts, projectRoot, srcDir, current, entries)
+		if err != nil {
+			fmt.Fprintf(logOut, "⚠️  Failed to write report: %v\n", err)
+			continue
+		}
+		fmt.Fprintf(logOut, "🔄 Regenerated %d @fileoverview blocks to %s in %s\n", len(entries), destination, time.Since(start))
+	}
+
+	return nil
+}
+
+func loadWatchedFile(filePath string, opts ScriptOptions, projectRoot string) (watchedFile, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return watchedFile{}, err
+	}
+
+	entry, found, err := readEntry(filePath, opts.CheckLines, projectRoot)
+	if err != nil {
+		return watchedFile{}, err
+	}
+	if found && opts.GitDates {
+		single := []FileOverviewEntry{entry}
+		annotateGitDates(single, projectRoot, io.Discard)
+		entry = single[0]
+	}
+
+	return watchedFile{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		entry:   entry,
+		found:   found,
+	}, nil
+}
+
 func run() error {
 	start := time.Now()
 
@@ -230,43 +824,56 @@ func run() error {
 
 	info, err := os.Stat(srcDir)
 	if err != nil || !info.IsDir() {
-		// Matches TS behavior: direct message and exit with code 1,
-		// no "Error extracting fileoverviews" wrapper here.
+		// Matches TS behavior: direct message, no "Error extracting
+		// fileoverviews" wrapper here.
 		fmt.Fprintf(os.Stderr, "Directory not found: %s\n", srcDir)
-		os.Exit(1)
+		os.Exit(ExitError)
 	}
 
-	files, err := collectSourceFiles(srcDir)
+	files, err := collectSourceFiles(srcDir, projectRoot, opts.Filter)
 	if err != nil {
 		return err
 	}
 
-	entries, err := buildReportEntries(files, opts.CheckLines, opts.Debug, projectRoot)
+	// When the report itself goes to stdout, status output moves to stderr.
+	var logOut io.Writer = os.Stdout
+	if opts.Output == StdoutOutput {
+		logOut = os.Stderr
+	}
+
+	entries, err := buildReportEntries(files, opts.CheckLines, opts.Debug, projectRoot, opts.Workers, logOut)
 	if err != nil {
 		return err
 	}
 
-	markdown := buildMarkdown(entries, len(files))
-	outputPath := filepath.Join(projectRoot, opts.Output)
+	if opts.GitDates {
+		annotateGitDates(entries, projectRoot, logOut)
+	}
 
-	if err := os.WriteFile(outputPath, []byte(markdown), 0o644); err != nil {
+	destination, err := writeReport(opts, projectRoot, srcDir, files, entries)
+	if err != nil {
 		return err
 	}
 
 	elapsed := time.Since(start)
-	fmt.Printf(
+	fmt.Fprintf(
+		logOut,
 		"✅ Extracted %d @fileoverview blocks to %s in %s\n",
 		len(entries),
-		opts.Output,
+		destination,
 		elapsed,
 	)
 
+	if opts.Watch {
+		return watch(opts, projectRoot, srcDir, files, entries, logOut)
+	}
+
 	return nil
 }
 
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error extracting fileoverviews: %v\n", err)
-		os.Exit(1)
+		os.Exit(ExitError)
 	}
 }
@@ -0,0 +1,95 @@
+//go:build ignore
+
+package main
+
+import "testing"
+
+// Run with: go test scripts/extract-fileoverview.go scripts/extract-fileoverview_test.go
+
+func TestExtractOverviewBlock(t *testing.T) {
+	cases := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{
+			name:    "single line",
+			snippet: "/** @fileoverview Does the thing. */",
+			want:    "Does the thing.",
+		},
+		{
+			name:    "single line without padding",
+			snippet: "/**@fileoverview Does the thing.*/",
+			want:    "Does the thing.",
+		},
+		{
+			name:    "single line with double-star terminator",
+			snippet: "/** @fileoverview Does the thing. **/",
+			want:    "Does the thing.",
+		},
+		{
+			name:    "single line starting with emphasis",
+			snippet: "/** @fileoverview *Experimental* bridge module. */",
+			want:    "*Experimental* bridge module.",
+		},
+		{
+			name:    "single line followed by code",
+			snippet: "/** @fileoverview Does the thing. */\nconst x = 1; /* trailing */",
+			want:    "Does the thing.",
+		},
+		{
+			name:    "single line after use strict",
+			snippet: "'use strict';\n/** @fileoverview Does the thing. */",
+			want:    "Does the thing.",
+		},
+		{
+			name:    "multi line",
+			snippet: "/**\n * @fileoverview First line.\n * Second line.\n */",
+			want:    "First line.\nSecond line.",
+		},
+		{
+			name:    "multi line with CRLF",
+			snippet: "/**\r\n * @fileoverview First line.\r\n * Second line.\r\n */",
+			want:    "First line.\nSecond line.",
+		},
+		{
+			name:    "empty tag",
+			snippet: "/** @fileoverview */",
+			want:    "",
+		},
+		{
+			name:    "no tag",
+			snippet: "/** Just a doc comment. */",
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := extractOverviewBlock(tc.snippet); got != tc.want {
+				t.Errorf("extractOverviewBlock(%q) = %q, want %q", tc.snippet, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCountLines(t *testing.T) {
+	cases := []struct {
+		content string
+		want    int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"a\nb\n", 2},
+		{"a\nb", 2},
+		{"a\r\nb\r\n\r\nc", 4},
+		{"a\rb\r", 2},
+		{"a\r\rb", 3},
+	}
+
+	for _, tc := range cases {
+		if got := countLines(tc.content); got != tc.want {
+			t.Errorf("countLines(%q) = %d, want %d", tc.content, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,1062 @@
+//go:build ignore
+
+// ff-scan.go
+//
+// Single entry point for the repository scanners. Each scanner stays a
+// standalone `go run scripts/<name>.go` program with its own flags; ff-scan
+// builds the one named by the subcommand and runs it with the remaining
+// arguments, passing its output and exit code straight through.
+//
+// Usage:
+//   go run scripts/ff-scan.go <subcommand> [flags]
+//   go run scripts/ff-scan.go help
+//   go run scripts/ff-scan.go <subcommand> --help   (the scanner's own flags)
+//   go run scripts/ff-scan.go all --format=json      (merged findings)
+//   go run scripts/ff-scan.go all --format=html --out=dashboard.html
+//   go run scripts/ff-scan.go all --changed            (this branch's files only)
+//   go run scripts/ff-scan.go install-hooks            (git pre-commit hook)
+//
+// Run from the repository root, like the scanners themselves.
+//
+// Common settings (extra excludes, file extensions, output format, color, and
+// worker count) can live in ff-scan.json at the repository root. Only ff-scan
+// reads the file: a scanner run directly with `go run scripts/<name>.go`
+// ignores it and takes nothing but its own flags. ff-scan passes each setting
+// to the scanners that have a matching flag, warning on stderr when one has
+// none, and a flag given on the command line wins over the file. "scanners"
+// overrides settings per subcommand:
+//
+//   {
+//     "exclude": ["fixtures"],
+//     "extensions": [".ts", ".tsx"],
+//     "format": "json",
+//     "color": "never",
+//     "workers": 8,
+//     "failOn": "error",
+//     "scanners": {
+//       "eslint-disable": {"format": "markdown", "baseline": "eslint-disable-baseline.json"},
+//       "console": {"severity": "error=error,debug=info"}
+//     },
+//     "enabled": ["console", "eslint-disable", "lucide"]
+//   }
+//
+// `all` runs every scanner with a JSON report (or only those listed in
+// "enabled") and merges their findings into one document. Every finding has
+// the same shape, which console, window, and eslint-disable also use for their
+// own --format=json; the other scanners' reports are converted:
+//
+//   {"tool": "console", "file": "src/main/index.ts", "line": 12,
+//    "severity": "warning", "message": "console.log(x);", "kind": "log"}
+//
+// severity is error, warning, or info; line is 0 for whole-file findings.
+//
+// Every scanner except extract-fileoverview walks its directories with
+// scan.WalkSourceFiles, skipping node_modules, dist, build, out, coverage, lib,
+// and the other scan.DefaultExcludes. --exclude adds to them: a bare name such
+// as fixtures skips directories with that name, and a glob such as
+// **/*.spec.ts skips the files it matches. --use-gitignore also skips files
+// ignored by git.
+//
+// Every scanner exits 0 when clean, 1 when findings exceed a threshold it was
+// asked to enforce, and 2 on invalid usage or a runtime/I/O error. The
+// thresholds are opt-in: --fail-on-match fails on any finding and --max=N on
+// more than N (check-fileoverview and count-lines keep --fail-on-missing and
+// --fail-over, with --fail-on-match as an alias; detect-hardcoded-css calls
+// its limit --max-allowed, with --max as an alias). extract-fileoverview only
+// reports, so it exits 0 or 2.
+//
+// Every scanner except extract-fileoverview accepts --changed, limiting it to
+// the files changed between --changed-base (default origin/main) and HEAD, and
+// --staged, limiting it to the files staged for commit. Both read the working
+// tree copy of each file. `all` passes them through to every scanner.
+//
+// Every scanner except extract-fileoverview also accepts --color=auto|always|never
+// for its text report. auto, the default, colors only a terminal and honours
+// NO_COLOR; machine-readable formats and --out files are never colored.
+//
+// Every scanner except extract-fileoverview accepts --baseline=FILE, reporting
+// (and counting towards --max and --fail-on-match) only findings missing from
+// the file, and --update-baseline to rewrite it from the current findings.
+// Every baseline is the same JSON document, read and written by
+// scripts/internal/scan: a count per file and stable per-tool key rather than
+// line numbers, so it survives unrelated edits. console and window key by the
+// line text, lucide by the referencing line, css by the color kind and value,
+// eslint-disable by the rule, and check-fileoverview by the issue kind.
+// count-lines records the line count of each file over --max-lines under
+// "lines", failing only once it grows. Each scanner needs its own file, so
+// "baseline" is only accepted under "scanners" in ff-scan.json.
+//
+// Every scanner except extract-fileoverview accepts --stats, printing the
+// footer written by scan.PrintStats, and --quiet, which drops the footer
+// along with the scanner's timing lines:
+//
+//   stats: tool=console files=346 lines=88164 matches=1154 elapsed=142.49ms
+//
+// `all` reads the footers back with scan.ParseStats into a table on stderr
+// (skipped with --quiet) and into the "stats" of each scanner in the merged
+// report.
+//
+// Every scanner except extract-fileoverview shares a severity engine.
+// --severity=KIND=LEVEL,... sets the level (error, warning, or info) of a
+// finding kind: the color kind for css, the console level, "window-access"
+// for window, the issue kind (missing, misplaced, empty, missing-tags) for
+// fileoverview, the directive type (file, block, line, next-line) for
+// eslint-disable, "over-limit" (a file over --max-lines) for lines, and
+// icon-import, unknown-icon, deprecated-icon, or dynamic-icon-usage for
+// lucide. --fail-on=LEVEL[:N] exits 1 when more than N findings (default 0)
+// are at LEVEL or above. The levels also fill in "severity" in the JSON
+// findings. "failOn" can be shared in ff-scan.json, but kinds differ
+// per scanner, so "severity" is only accepted under "scanners".
+//
+// install-hooks writes .git/hooks/pre-commit, which runs the subcommands named
+// by --scanners (default fileoverview,eslint-disable) on the staged files and
+// blocks the commit when one fails. It refuses to replace an existing hook
+// unless given --force.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
+)
+
+const (
+	// scriptsDir holds the scanner sources, relative to the repository root
+	scriptsDir = "scripts"
+	// configFile holds the shared settings, relative to the repository root
+	configFile = "ff-scan.json"
+	// largeFileLines is the line count from which `all` reports a file as large
+	largeFileLines = 500
+)
+
+// Output formats accepted by `all --format`
+const (
+	formatJSON = "json"
+	formatHTML = "html"
+)
+
+// exitError is the exit code for invalid usage or a failure to run a scanner
+const exitError = 2
+
+// subcommand maps an ff-scan subcommand to the scanner source it runs
+type subcommand struct {
+	name        string
+	script      string
+	description string
+	flags       settingFlags
+	// reportArgs make the scanner print a JSON report that parseReport turns
+	// into findings and an optional one-line summary for `all`; scanners
+	// without one are left out of `all`
+	reportArgs  []string
+	parseReport func(data []byte) ([]finding, string, error)
+	// hookArgs run the scanner on staged files from the pre-commit hook,
+	// failing on findings; scanners without them cannot be used in the hook
+	hookArgs []string
+}
+
+// settingFlags names the scanner flag that receives each shared setting;
+// an empty name means the scanner has no such flag, and configArgs skips the
+// setting with a warning
+type settingFlags struct {
+	exclude    string
+	extensions string
+	format     string
+	color      string
+	baseline   string
+	severity   string
+	failOn     string
+	workers    string
+}
+
+var subcommands = []subcommand{
+	{
+		name:        "css",
+		script:      "detect-hardcoded-css.go",
+		description: "Find hard-coded colors in stylesheets and components",
+		flags:       settingFlags{exclude: "exclude", extensions: "ext", color: "color", baseline: "baseline", severity: "severity", failOn: "fail-on", workers: "workers"},
+		reportArgs:  []string{"-format=json"},
+		parseReport: parseCSSReport,
+	},
+	{
+		name:        "console",
+		script:      "find-console-usage.go",
+		description: "List console.* calls by level",
+		flags:       settingFlags{exclude: "exclude", color: "color", baseline: "baseline", severity: "severity", failOn: "fail-on", workers: "workers"},
+		reportArgs:  []string{"--format=json", "--levels=log,debug,info,warn,error"},
+		parseReport: parseFindings,
+		hookArgs:    []string{"--staged", "--fail-on-match"},
+	},
+	{
+		name:        "window",
+		script:      "find-window-usage.go",
+		description: "List window.* accesses with context",
+		flags:       settingFlags{exclude: "exclude", extensions: "extensions", color: "color", baseline: "baseline", severity: "severity", failOn: "fail-on", workers: "workers"},
+		reportArgs:  []string{"--format=json"},
+		parseReport: parseFindings,
+		hookArgs:    []string{"--staged", "--fail-on-match"},
+	},
+	{
+		name:        "fileoverview",
+		script:      "check-fileoverview.go",
+		description: "Check that source files start with an @fileoverview header",
+		flags:       settingFlags{exclude: "exclude", format: "format", color: "color", baseline: "baseline", severity: "severity", failOn: "fail-on", workers: "workers"},
+		reportArgs:  []string{"--format=json"},
+		parseReport: parseFileoverviewReport,
+		hookArgs:    []string{"--staged", "--fail-on-missing"},
+	},
+	{
+		name:        "fileoverview-report",
+		script:      "extract-fileoverview.go",
+		description: "Collect @fileoverview blocks into a Markdown report",
+		flags:       settingFlags{workers: "workers"},
+	},
+	{
+		name:        "eslint-disable",
+		script:      "scan-eslint-disable.go",
+		description: "Audit eslint-disable and other suppression directives",
+		flags:       settingFlags{exclude: "exclude", format: "format", color: "color", baseline: "baseline", severity: "severity", failOn: "fail-on", workers: "workers"},
+		reportArgs:  []string{"--format=json"},
+		parseReport: parseFindings,
+		hookArgs:    []string{"--staged", "--require-reason"},
+	},
+	{
+		name:        "lines",
+		script:      "count-lines.go",
+		description: "Count lines per file and directory",
+		flags:       settingFlags{exclude: "exclude", extensions: "ext", format: "format", color: "color", baseline: "baseline", severity: "severity", failOn: "fail-on", workers: "workers"},
+		reportArgs:  []string{"--format=json", "--min-lines=" + strconv.Itoa(largeFileLines)},
+		parseReport: parseLinesReport,
+		hookArgs:    []string{"--staged", "--max-lines=" + strconv.Itoa(largeFileLines), "--fail-over"},
+	},
+	{
+		name:        "lucide",
+		script:      "find-lucide-usage.go",
+		description: "Inventory Lucide icon imports and usage",
+		flags:       settingFlags{exclude: "exclude", format: "format", color: "color", baseline: "baseline", severity: "severity", failOn: "fail-on", workers: "workers"},
+		reportArgs:  []string{"--format=json"},
+		parseReport: parseLucideReport,
+	},
+}
+
+// finding is one result in the schema shared by every scanner
+type finding struct {
+	Tool     string `json:"tool"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Kind     string `json:"kind"`
+}
+
+// mergedReport is the document written by `all --format=json`
+type mergedReport struct {
+	Scanners []scannerRun `json:"scanners"`
+	Findings []finding    `json:"findings"`
+}
+
+// scannerRun records how one scanner went during `all`
+type scannerRun struct {
+	Tool     string `json:"tool"`
+	Findings int    `json:"findings"`
+	// Summary is the scanner's headline, or its finding counts per kind
+	Summary  string `json:"summary"`
+	ExitCode int    `json:"exitCode"`
+	// Stats is the scanner's --stats footer, when it printed one
+	Stats *scan.Stats `json:"stats,omitempty"`
+}
+
+// scanSettings are the settings shared by the scanners
+type scanSettings struct {
+	Exclude    []string `json:"exclude"`
+	Extensions []string `json:"extensions"`
+	Format     string   `json:"format"`
+	Color      string   `json:"color"`
+	Baseline   string   `json:"baseline"`
+	Severity   string   `json:"severity"`
+	FailOn     string   `json:"failOn"`
+	Workers    int      `json:"workers"`
+}
+
+// scanConfig is the layout of ff-scan.json
+type scanConfig struct {
+	scanSettings
+	Scanners map[string]scanSettings `json:"scanners"`
+	// Enabled limits `all` to these scanners
+	Enabled []string `json:"enabled"`
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage(os.Stderr)
+		os.Exit(exitError)
+	}
+
+	name := os.Args[1]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		printUsage(os.Stdout)
+		return
+	case "install-hooks":
+		if err := installHooks(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "ff-scan install-hooks: %v\n", err)
+			os.Exit(exitError)
+		}
+		return
+	}
+
+	cmd := findSubcommand(name)
+	if cmd == nil && name != "all" {
+		fmt.Fprintf(os.Stderr, "unknown subcommand: %s\n\n", name)
+		printUsage(os.Stderr)
+		os.Exit(exitError)
+	}
+
+	config, err := loadConfig(configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load %s: %v\n", configFile, err)
+		os.Exit(exitError)
+	}
+
+	if cmd == nil {
+		code, err := runAll(config, os.Args[2:])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ff-scan all: %v\n", err)
+			os.Exit(exitError)
+		}
+		os.Exit(code)
+	}
+
+	args := append(configArgs(*cmd, config, os.Args[2:]), os.Args[2:]...)
+	code, err := runScanner(*cmd, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ff-scan %s: %v\n", cmd.name, err)
+		os.Exit(exitError)
+	}
+	os.Exit(code)
+}
+
+func findSubcommand(name string) *subcommand {
+	for i := range subcommands {
+		if subcommands[i].name == name {
+			return &subcommands[i]
+		}
+	}
+	return nil
+}
+
+// loadConfig reads the shared settings, returning an empty config when the
+// file does not exist.
+func loadConfig(path string) (scanConfig, error) {
+	var config scanConfig
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return config, err
+	}
+	if config.Baseline != "" {
+		return config, errors.New("\"baseline\" must be set per scanner under \"scanners\"")
+	}
+	if config.Severity != "" {
+		return config, errors.New("\"severity\" must be set per scanner under \"scanners\"")
+	}
+	for name := range config.Scanners {
+		if findSubcommand(name) == nil {
+			return config, fmt.Errorf("unknown scanner in \"scanners\": %s", name)
+		}
+	}
+	for _, name := range config.Enabled {
+		cmd := findSubcommand(name)
+		if cmd == nil {
+			return config, fmt.Errorf("unknown scanner in \"enabled\": %s", name)
+		}
+		if cmd.parseReport == nil {
+			return config, fmt.Errorf("scanner in \"enabled\" has no JSON report for `all`: %s", name)
+		}
+	}
+	return config, nil
+}
+
+// configArgs turns the settings for cmd into flags for its scanner, leaving
+// out flags already given in args. A configured setting the scanner has no
+// flag for is left out too, with a warning on stderr.
+func configArgs(cmd subcommand, config scanConfig, args []string) []string {
+	settings := config.scanSettings
+	if override, ok := config.Scanners[cmd.name]; ok {
+		if override.Exclude != nil {
+			settings.Exclude = override.Exclude
+		}
+		if override.Extensions != nil {
+			settings.Extensions = override.Extensions
+		}
+		if override.Format != "" {
+			settings.Format = override.Format
+		}
+		if override.Color != "" {
+			settings.Color = override.Color
+		}
+		if override.Baseline != "" {
+			settings.Baseline = override.Baseline
+		}
+		if override.Severity != "" {
+			settings.Severity = override.Severity
+		}
+		if override.FailOn != "" {
+			settings.FailOn = override.FailOn
+		}
+		if override.Workers != 0 {
+			settings.Workers = override.Workers
+		}
+	}
+
+	var flags []string
+	add := func(setting, name, value string) {
+		switch {
+		case value == "":
+		case name == "":
+			// Nothing is lost when args already fix it, as `all` does for format
+			if !hasFlag(args, setting) {
+				fmt.Fprintf(os.Stderr, "ff-scan %s: ignoring the %q setting in %s; the scanner has no flag for it\n", cmd.name, setting, configFile)
+			}
+		case !hasFlag(args, name):
+			flags = append(flags, fmt.Sprintf("--%s=%s", name, value))
+		}
+	}
+	add("exclude", cmd.flags.exclude, strings.Join(settings.Exclude, ","))
+	add("extensions", cmd.flags.extensions, strings.Join(settings.Extensions, ","))
+	add("format", cmd.flags.format, settings.Format)
+	add("color", cmd.flags.color, settings.Color)
+	add("baseline", cmd.flags.baseline, settings.Baseline)
+	add("severity", cmd.flags.severity, settings.Severity)
+	add("failOn", cmd.flags.failOn, settings.FailOn)
+	if settings.Workers > 0 {
+		add("workers", cmd.flags.workers, strconv.Itoa(settings.Workers))
+	}
+	return flags
+}
+
+// hasFlag reports whether args sets the named flag, as -name, --name,
+// -name=value, or --name=value, before any "--" terminator.
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		arg = strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if key, _, _ := strings.Cut(arg, "="); key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runScanner builds the scanner into a temporary directory and runs it with
+// args, returning its exit code.
+func runScanner(cmd subcommand, args []string) (int, error) {
+	buildDir, err := os.MkdirTemp("", "ff-scan-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(buildDir)
+
+	binary, err := buildScanner(cmd, buildDir)
+	if err != nil {
+		return 0, err
+	}
+	return execScanner(binary, args, os.Stdout, os.Stderr)
+}
+
+// buildScanner compiles the scanner into dir and returns the binary path.
+// Building rather than `go run` keeps the scanner's own exit code, which
+// `go run` collapses to 1.
+func buildScanner(cmd subcommand, dir string) (string, error) {
+	source := filepath.Join(scriptsDir, cmd.script)
+	if _, err := os.Stat(source); err != nil {
+		return "", fmt.Errorf("scanner source not found: %s (run ff-scan from the repository root)", source)
+	}
+
+	binary := filepath.Join(dir, cmd.name)
+	build := exec.Command("go", "build", "-o", binary, source)
+	build.Stdout = os.Stderr
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		return "", fmt.Errorf("failed to build %s: %w", source, err)
+	}
+	return binary, nil
+}
+
+// execScanner runs a built scanner with its output on stdout and stderr and
+// returns its exit code.
+func execScanner(binary string, args []string, stdout, stderr io.Writer) (int, error) {
+	scanner := exec.Command(binary, args...)
+	scanner.Stdin = os.Stdin
+	scanner.Stdout = stdout
+	scanner.Stderr = stderr
+	err := scanner.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// runAll runs every enabled scanner that has a JSON report and writes their
+// findings as one merged document, followed by a table of the scanners'
+// --stats footers on stderr. Its exit code is the highest of the scanners',
+// so an error outranks a failed threshold.
+func runAll(config scanConfig, args []string) (int, error) {
+	fs := flag.NewFlagSet("all", flag.ExitOnError)
+	format := fs.String("format", formatJSON, "Output format: json, or html for a self-contained dashboard")
+	outPath := fs.String("out", "", "Write the report to this file instead of stdout")
+	changed := fs.Bool("changed", false, "Only scan files changed on this branch (git diff --name-only <base>...HEAD)")
+	changedBase := fs.String("changed-base", "origin/main", "Base ref used by --changed")
+	staged := fs.Bool("staged", false, "Only scan files staged for commit")
+	quiet := fs.Bool("quiet", false, "Skip the stats table and the scanners' timing lines")
+	if err := fs.Parse(args); err != nil {
+		return 0, err
+	}
+	if *format != formatJSON && *format != formatHTML {
+		return 0, fmt.Errorf("invalid value for --format: %s (expected json or html)", *format)
+	}
+
+	buildDir, err := os.MkdirTemp("", "ff-scan-")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(buildDir)
+
+	var changedArgs []string
+	if *changed {
+		changedArgs = append(changedArgs, "--changed", "--changed-base="+*changedBase)
+	}
+	if *staged {
+		changedArgs = append(changedArgs, "--staged")
+	}
+	statsArg := "--stats"
+	if *quiet {
+		statsArg = "--quiet"
+	}
+
+	report := mergedReport{Scanners: []scannerRun{}, Findings: []finding{}}
+	exitCode := 0
+	for _, cmd := range enabledScanners(config) {
+		binary, err := buildScanner(cmd, buildDir)
+		if err != nil {
+			return 0, err
+		}
+
+		var stdout, stderr bytes.Buffer
+		scannerArgs := append(configArgs(cmd, config, cmd.reportArgs), cmd.reportArgs...)
+		scannerArgs = append(scannerArgs, changedArgs...)
+		scannerArgs = append(scannerArgs, statsArg)
+		code, err := execScanner(binary, scannerArgs, &stdout, &stderr)
+		stats := forwardStderr(stderr.Bytes(), os.Stderr)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", cmd.name, err)
+		}
+		findings, summary, err := cmd.parseReport(stdout.Bytes())
+		if err != nil {
+			return 0, fmt.Errorf("%s: failed to parse report: %w", cmd.name, err)
+		}
+		if summary == "" {
+			summary = summarizeKinds(findings)
+		}
+
+		for i := range findings {
+			findings[i].Tool = cmd.name
+		}
+		report.Findings = append(report.Findings, findings...)
+		report.Scanners = append(report.Scanners, scannerRun{
+			Tool:     cmd.name,
+			Findings: len(findings),
+			Summary:  summary,
+			ExitCode: code,
+			Stats:    stats,
+		})
+		if code > exitCode {
+			exitCode = code
+		}
+	}
+
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		outFile, err := os.Create(*outPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create --out file: %w", err)
+		}
+		defer outFile.Close()
+		out = outFile
+	}
+
+	if *format == formatHTML {
+		err = writeDashboard(out, report)
+	} else {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !*quiet {
+		printStatsTable(os.Stderr, report.Scanners)
+	}
+	return exitCode, nil
+}
+
+// forwardStderr copies a scanner's stderr to w, except for its --stats
+// footer, which it returns parsed (nil when the scanner printed none)
+func forwardStderr(data []byte, w io.Writer) *scan.Stats {
+	var stats *scan.Stats
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if parsed, ok := scan.ParseStats(strings.TrimSpace(line)); ok {
+			stats = &parsed
+			continue
+		}
+		io.WriteString(w, line)
+	}
+	return stats
+}
+
+// printStatsTable writes one row per scanner run by `all` and a total row;
+// scanners that printed no --stats footer show dashes
+func printStatsTable(w io.Writer, runs []scannerRun) {
+	const row = "%-16s %8s %9s %8s %11s\n"
+	fmt.Fprintf(w, "\n"+row, "Scanner", "Files", "Lines", "Matches", "Elapsed")
+	var total scan.Stats
+	for _, run := range runs {
+		if run.Stats == nil {
+			fmt.Fprintf(w, row, run.Tool, "-", "-", "-", "-")
+			continue
+		}
+		fmt.Fprintf(w, row, run.Tool, strconv.Itoa(run.Stats.Files), strconv.Itoa(run.Stats.Lines),
+			strconv.Itoa(run.Stats.Matches), formatElapsed(run.Stats.Elapsed))
+		total.Files += run.Stats.Files
+		total.Lines += run.Stats.Lines
+		total.Matches += run.Stats.Matches
+		total.Elapsed += run.Stats.Elapsed
+	}
+	fmt.Fprintf(w, row, "Total", strconv.Itoa(total.Files), strconv.Itoa(total.Lines),
+		strconv.Itoa(total.Matches), formatElapsed(total.Elapsed))
+}
+
+// formatElapsed renders a duration in milliseconds, as the footers print it
+func formatElapsed(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(d.Microseconds())/1000)
+}
+
+// hookTemplate is the pre-commit hook written by install-hooks; each scanner
+// line is `go run scripts/ff-scan.go <subcommand> <hookArgs>`
+const hookTemplate = `#!/bin/sh
+# Generated by ` + "`go run scripts/ff-scan.go install-hooks`" + `; rerun it with --force
+# to regenerate. Runs the ff-scan checks on the files staged for commit.
+
+if git diff --cached --quiet; then
+	exit 0
+fi
+
+status=0
+%s
+
+if [ "$status" -ne 0 ]; then
+	echo "ff-scan: pre-commit checks failed on the staged files." >&2
+	echo "Fix the findings above and stage the fixes, or commit with --no-verify to skip the checks." >&2
+fi
+exit $status
+`
+
+// installHooks writes the git pre-commit hook running the --scanners
+// subcommands on staged files.
+func installHooks(args []string) error {
+	fs := flag.NewFlagSet("install-hooks", flag.ExitOnError)
+	var names string
+	fs.StringVar(&names, "scanners", "fileoverview,eslint-disable", "Comma-separated subcommands the hook runs")
+	force := fs.Bool("force", false, "Replace an existing pre-commit hook")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var lines []string
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		cmd := findSubcommand(name)
+		if cmd == nil {
+			return fmt.Errorf("unknown scanner in --scanners: %s", name)
+		}
+		if cmd.hookArgs == nil {
+			return fmt.Errorf("scanner cannot run in the pre-commit hook: %s", name)
+		}
+		lines = append(lines, fmt.Sprintf("go run %s/ff-scan.go %s %s || status=1",
+			scriptsDir, cmd.name, strings.Join(cmd.hookArgs, " ")))
+	}
+	if len(lines) == 0 {
+		return errors.New("--scanners names no scanner")
+	}
+
+	// --git-path honours core.hooksPath and linked worktrees
+	out, err := exec.Command("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return fmt.Errorf("failed to locate the git hooks directory: %w", err)
+	}
+	hooksDir := strings.TrimSpace(string(out))
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return err
+	}
+
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+	if _, err := os.Stat(hookPath); err == nil && !*force {
+		return fmt.Errorf("%s already exists (rerun with --force to replace it)", hookPath)
+	}
+	hook := fmt.Sprintf(hookTemplate, strings.Join(lines, "\n"))
+	if err := os.WriteFile(hookPath, []byte(hook), 0o755); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a file it replaces
+	if err := os.Chmod(hookPath, 0o755); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s running: %s\n", hookPath, names)
+	return nil
+}
+
+// summarizeKinds counts findings per kind, most frequent first, e.g.
+// "704 log, 450 error"
+func summarizeKinds(findings []finding) string {
+	if len(findings) == 0 {
+		return "none found"
+	}
+	counts := make(map[string]int)
+	var kinds []string
+	for _, f := range findings {
+		if counts[f.Kind] == 0 {
+			kinds = append(kinds, f.Kind)
+		}
+		counts[f.Kind]++
+	}
+	sort.SliceStable(kinds, func(i, j int) bool {
+		return counts[kinds[i]] > counts[kinds[j]]
+	})
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// dashboardSection is one scanner's card and detail table on the dashboard
+type dashboardSection struct {
+	scannerRun
+	Description string
+	Rows        []finding
+}
+
+// dashboardTemplate renders `all --format=html`: a grid of cards, one per
+// scanner, each linking to its findings table further down the page
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>ff-scan dashboard</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2rem; color: #1f2328; background: #f6f8fa; }
+  h1 { margin-top: 0; }
+  .cards { display: grid; grid-template-columns: repeat(auto-fill, minmax(15rem, 1fr)); gap: 1rem; }
+  .card { display: block; padding: 1rem; border-radius: 8px; background: #fff; border: 1px solid #d0d7de; color: inherit; text-decoration: none; }
+  .card:hover { border-color: #0969da; }
+  .card .count { font-size: 2.5rem; font-weight: 600; }
+  .card .summary, .card .description { color: #59636e; font-size: 0.9rem; }
+  .failed { border-left: 4px solid #cf222e; }
+  section { margin-top: 2.5rem; }
+  table { border-collapse: collapse; width: 100%; background: #fff; font-size: 0.85rem; }
+  th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.5rem; text-align: left; vertical-align: top; }
+  td.message { font-family: ui-monospace, monospace; white-space: pre-wrap; word-break: break-word; }
+  .error { color: #cf222e; } .warning { color: #9a6700; } .info { color: #59636e; }
+</style>
+</head>
+<body>
+<h1>ff-scan dashboard</h1>
+<div class="cards">
+{{- range .}}
+  <a class="card{{if .ExitCode}} failed{{end}}" href="#{{.Tool}}">
+    <div class="description">{{.Tool}} &middot; {{.Description}}</div>
+    <div class="count">{{.Findings}}</div>
+    <div class="summary">{{.Summary}}</div>
+  </a>
+{{- end}}
+</div>
+{{- range .}}
+<section id="{{.Tool}}">
+  <h2>{{.Tool}}</h2>
+  <p>{{.Description}} &mdash; {{.Summary}}{{if .ExitCode}} (exit code {{.ExitCode}}){{end}}</p>
+  {{- if .Rows}}
+  <table>
+    <thead><tr><th>File</th><th>Line</th><th>Severity</th><th>Kind</th><th>Message</th></tr></thead>
+    <tbody>
+    {{- range .Rows}}
+      <tr><td>{{.File}}</td><td>{{if .Line}}{{.Line}}{{end}}</td><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Kind}}</td><td class="message">{{.Message}}</td></tr>
+    {{- end}}
+    </tbody>
+  </table>
+  {{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// writeDashboard renders the merged report as a self-contained HTML page
+func writeDashboard(w io.Writer, report mergedReport) error {
+	rows := make(map[string][]finding)
+	for _, f := range report.Findings {
+		rows[f.Tool] = append(rows[f.Tool], f)
+	}
+
+	sections := make([]dashboardSection, 0, len(report.Scanners))
+	for _, run := range report.Scanners {
+		section := dashboardSection{scannerRun: run, Rows: rows[run.Tool]}
+		if cmd := findSubcommand(run.Tool); cmd != nil {
+			section.Description = cmd.description
+		}
+		sections = append(sections, section)
+	}
+	return dashboardTemplate.Execute(w, sections)
+}
+
+// enabledScanners returns the scanners `all` runs, in table order
+func enabledScanners(config scanConfig) []subcommand {
+	enabled := make(map[string]bool, len(config.Enabled))
+	for _, name := range config.Enabled {
+		enabled[name] = true
+	}
+
+	var cmds []subcommand
+	for _, cmd := range subcommands {
+		if cmd.parseReport == nil || (len(enabled) > 0 && !enabled[cmd.name]) {
+			continue
+		}
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+// parseFindings reads a report that already uses the shared schema
+func parseFindings(data []byte) ([]finding, string, error) {
+	var report struct {
+		Findings []finding `json:"findings"`
+	}
+	err := json.Unmarshal(data, &report)
+	return report.Findings, "", err
+}
+
+// parseFileoverviewReport turns each file missing an @fileoverview header
+// into a finding with the severity check-fileoverview gave its issue
+func parseFileoverviewReport(data []byte) ([]finding, string, error) {
+	var report struct {
+		TotalFiles      int     `json:"totalFiles"`
+		DocumentedCount int     `json:"documentedCount"`
+		CoveragePercent float64 `json:"coveragePercent"`
+		Missing         []struct {
+			File     string `json:"file"`
+			Issue    string `json:"issue"`
+			Severity string `json:"severity"`
+		} `json:"missing"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, "", err
+	}
+
+	findings := make([]finding, 0, len(report.Missing))
+	for _, missing := range report.Missing {
+		findings = append(findings, finding{
+			File:     missing.File,
+			Severity: missing.Severity,
+			Message:  missing.Issue,
+			Kind:     "missing-fileoverview",
+		})
+	}
+	summary := fmt.Sprintf("%.1f%% documented (%d of %d files)", report.CoveragePercent, report.DocumentedCount, report.TotalFiles)
+	return findings, summary, nil
+}
+
+// parseCSSReport turns each color literal into a finding with the severity
+// detect-hardcoded-css gave its kind
+func parseCSSReport(data []byte) ([]finding, string, error) {
+	var report struct {
+		Findings []struct {
+			FilePath string `json:"filePath"`
+			Line     int    `json:"line"`
+			Matches  []struct {
+				Value    string `json:"value"`
+				Reason   string `json:"reason"`
+				Kind     string `json:"kind"`
+				Severity string `json:"severity"`
+			} `json:"matches"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, "", err
+	}
+
+	var findings []finding
+	for _, f := range report.Findings {
+		for _, m := range f.Matches {
+			findings = append(findings, finding{
+				File:     f.FilePath,
+				Line:     f.Line,
+				Severity: m.Severity,
+				Message:  fmt.Sprintf("%s (%s)", m.Value, m.Reason),
+				Kind:     m.Kind,
+			})
+		}
+	}
+	return findings, "", nil
+}
+
+// parseLinesReport turns each file of largeFileLines or more into an info
+// finding
+func parseLinesReport(data []byte) ([]finding, string, error) {
+	var report struct {
+		Files []struct {
+			Path  string `json:"path"`
+			Lines int    `json:"lines"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, "", err
+	}
+
+	findings := make([]finding, 0, len(report.Files))
+	for _, file := range report.Files {
+		findings = append(findings, finding{
+			File:     file.Path,
+			Severity: "info",
+			Message:  fmt.Sprintf("%d lines", file.Lines),
+			Kind:     "large-file",
+		})
+	}
+	summary := fmt.Sprintf("%d files with %d+ lines", len(findings), largeFileLines)
+	return findings, summary, nil
+}
+
+// parseLucideReport turns icon imports, icons outside the --allowed set, and
+// dynamic and deprecated usage into findings at the level the report assigns
+// each kind
+func parseLucideReport(data []byte) ([]finding, string, error) {
+	type iconImport struct {
+		File   string `json:"file"`
+		Line   int    `json:"line"`
+		Icon   string `json:"icon"`
+		Source string `json:"source"`
+	}
+	var report struct {
+		IconUses     map[string]int `json:"iconUses"`
+		Imports      []iconImport   `json:"imports"`
+		UnknownIcons []iconImport   `json:"unknownIcons"`
+		DynamicUsage []struct {
+			File   string `json:"file"`
+			Line   int    `json:"line"`
+			Reason string `json:"reason"`
+		} `json:"dynamicUsage"`
+		DeprecatedIcons []struct {
+			File        string `json:"file"`
+			Line        int    `json:"line"`
+			Icon        string `json:"icon"`
+			Replacement string `json:"replacement"`
+		} `json:"deprecatedIcons"`
+		Severity map[string]string `json:"severity"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, "", err
+	}
+	// Reports without levels keep the scanner's defaults
+	severity := func(kind, fallback string) string {
+		if level := report.Severity[kind]; level != "" {
+			return level
+		}
+		return fallback
+	}
+
+	var findings []finding
+	for _, imp := range report.Imports {
+		findings = append(findings, finding{
+			File:     imp.File,
+			Line:     imp.Line,
+			Severity: severity("icon-import", "info"),
+			Message:  fmt.Sprintf("%s imported from %s", imp.Icon, imp.Source),
+			Kind:     "icon-import",
+		})
+	}
+	for _, imp := range report.UnknownIcons {
+		findings = append(findings, finding{
+			File:     imp.File,
+			Line:     imp.Line,
+			Severity: severity("unknown-icon", "error"),
+			Message:  fmt.Sprintf("%s is not in the approved icon set", imp.Icon),
+			Kind:     "unknown-icon",
+		})
+	}
+	for _, usage := range report.DynamicUsage {
+		findings = append(findings, finding{
+			File:     usage.File,
+			Line:     usage.Line,
+			Severity: severity("dynamic-icon-usage", "warning"),
+			Message:  usage.Reason,
+			Kind:     "dynamic-icon-usage",
+		})
+	}
+	for _, icon := range report.DeprecatedIcons {
+		findings = append(findings, finding{
+			File:     icon.File,
+			Line:     icon.Line,
+			Severity: severity("deprecated-icon", "warning"),
+			Message:  fmt.Sprintf("%s is deprecated; use %s", icon.Icon, icon.Replacement),
+			Kind:     "deprecated-icon",
+		})
+	}
+	summary := fmt.Sprintf("%d distinct icons in %d imports", len(report.IconUses), len(report.Imports))
+	return findings, summary, nil
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: go run scripts/ff-scan.go <subcommand> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Subcommands:")
+	for _, cmd := range subcommands {
+		fmt.Fprintf(w, "  %-20s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintf(w, "  %-20s %s\n", "all", "Run every scanner with a JSON report and merge the findings (--format=json|html, --out=FILE, --changed, --staged, --quiet)")
+	fmt.Fprintf(w, "  %-20s %s\n", "install-hooks", "Write a git pre-commit hook running --scanners on staged files (--force to replace)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run `ff-scan <subcommand> --help` for the flags of each scanner.")
+}
@@ -1,15 +1,24 @@
+//go:build ignore
+
 package main
 
 import (
 	"bufio"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
 )
 
 // Global configuration constants matching the source script
@@ -17,13 +26,35 @@ var (
 	supportedExtensions = map[string]bool{
 		".ts": true, ".tsx": true, ".js": true, ".jsx": true,
 	}
-	excludedDirs = map[string]bool{
-		"node_modules": true, ".git": true, "dist": true, "build": true, "out": true,
-	}
 	validLevels     = []string{"log", "debug", "info", "warn", "error"}
 	validLevelSet   = make(map[string]bool)
 	defaultLevel    = "log"
 	projectRoot     string
+	// logOut receives informational lines; stderr when stdout carries JSON
+	logOut io.Writer = os.Stdout
+)
+
+// Exit codes shared by every scanner
+const (
+	exitFindings = 1 // findings over a --fail-on-* / --max threshold
+	exitError    = 2 // invalid usage or a runtime/I/O error
+)
+
+// exitCode is set by run when a --fail-on-match, --max, or --fail-on check fails
+var exitCode int
+
+// run fills in stats for the --stats footer, which --quiet suppresses along
+// with the timing line
+var (
+	showStats bool
+	quiet     bool
+	stats     scan.Stats
+)
+
+// Output formats accepted by --format
+const (
+	formatText = "text"
+	formatJSON = "json"
 )
 
 func init() {
@@ -40,6 +71,23 @@ type ConsoleMatch struct {
 	Level   string
 }
 
+// jsonFinding is one finding in the --format=json report, in the schema
+// shared by every scanner (see scripts/ff-scan.go)
+type jsonFinding struct {
+	Tool     string `json:"tool"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Kind     string `json:"kind"`
+}
+
+// jsonReport is the document written by --format=json
+type jsonReport struct {
+	Tool     string        `json:"tool"`
+	Findings []jsonFinding `json:"findings"`
+}
+
 // Mandatory execution timing wrapper
 func main() {
 	start := time.Now()
@@ -47,13 +95,20 @@ func main() {
 	// Defer the timing print to ensure it runs on exit
 	defer func() {
 		elapsed := time.Since(start)
-		// Formatting to match the source script's specific output style + prompt requirement
-		fmt.Printf("\nScan completed in %.2fms\n", float64(elapsed.Microseconds())/1000.0)
+		if !quiet {
+			// Formatting to match the source script's specific output style + prompt requirement
+			fmt.Fprintf(logOut, "\nScan completed in %.2fms\n", float64(elapsed.Microseconds())/1000.0)
+			if showStats {
+				stats.Elapsed = elapsed
+				scan.PrintStats(logOut, "console", stats)
+			}
+		}
+		os.Exit(exitCode)
 	}()
 
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error finding console usage: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 }
 
@@ -62,12 +117,54 @@ func run() error {
 	// 1. Parse Arguments
 	levelFlag := flag.String("level", "", "Single console level")
 	levelsFlag := flag.String("levels", "", "Comma-separated console levels")
+	formatFlag := flag.String("format", formatText, "Output format: text or json")
+	failOnMatchFlag := flag.Bool("fail-on-match", false, "Exit with code 1 when any console statement is found (same as --max=0)")
+	maxFlag := flag.Int("max", -1, "Exit with code 1 when more than N console statements are found (-1 disables the check)")
+	severityFlag := flag.String("severity", "", "Severity per console level as level=error|warning|info, e.g. log=error,debug=info (default warning)")
+	failOnFlag := flag.String("fail-on", "", "Exit with code 1 when more than N console statements (default 0) are at LEVEL severity or above, as LEVEL[:N]")
+	workersFlag := flag.Int("workers", runtime.NumCPU(), "Number of worker goroutines scanning files in parallel")
+	excludeFlag := flag.String("exclude", "", "Comma-separated globs of files to skip, e.g. **/*.spec.ts; a bare name such as fixtures skips directories with that name, in addition to node_modules, dist, and the other defaults")
+	useGitignoreFlag := flag.Bool("use-gitignore", false, "Skip files ignored by git (.gitignore, .git/info/exclude, global excludes)")
+	changedFlag := flag.Bool("changed", false, "Only scan files changed on this branch (git diff --name-only <base>...HEAD)")
+	changedBaseFlag := flag.String("changed-base", "origin/main", "Base ref used by --changed")
+	stagedFlag := flag.Bool("staged", false, "Only scan files staged for commit (git diff --cached --name-only), e.g. from a pre-commit hook")
+	baselineFlag := flag.String("baseline", "", "Baseline JSON of known console statements keyed by file and line text (e.g. console-baseline.json); only new ones are reported")
+	updateBaselineFlag := flag.Bool("update-baseline", false, "Rewrite the --baseline file with the current console statements and exit")
+	flag.BoolVar(&showStats, "stats", false, "Print a footer with files scanned, lines inspected, matches, and elapsed time")
+	flag.BoolVar(&quiet, "quiet", false, "Suppress the timing line and the --stats footer")
+	colorFlag := flag.String("color", scan.ColorAuto, "Color the text report: auto (terminal without NO_COLOR), always, or never")
 	flag.Parse()
 
+	if *workersFlag <= 0 {
+		*workersFlag = runtime.NumCPU()
+	}
+
+	switch *formatFlag {
+	case formatText:
+	case formatJSON:
+		logOut = os.Stderr
+	default:
+		return fmt.Errorf("invalid value for --format: %s (expected text or json)", *formatFlag)
+	}
+
+	enabled, err := scan.ColorEnabled(*colorFlag, os.Stdout)
+	if err != nil {
+		return err
+	}
+	scan.SetColor(enabled && *formatFlag == formatText)
+
 	levels := parseLevelsArg(*levelFlag, *levelsFlag)
 
+	defaultSeverities := make(map[string]string, len(validLevels))
+	for _, level := range validLevels {
+		defaultSeverities[level] = scan.SeverityWarning
+	}
+	policy, err := scan.NewSeverityPolicy(*severityFlag, *failOnFlag, defaultSeverities)
+	if err != nil {
+		return err
+	}
+
 	// 2. Setup paths
-	var err error
 	projectRoot, err = os.Getwd()
 	if err != nil {
 		return err
@@ -75,26 +172,97 @@ func run() error {
 	srcDir := filepath.Join(projectRoot, "src")
 
 	// 3. Collect Files
-	files, err := collectSourceFiles(srcDir)
+	excludes := scan.NewExcludes(strings.Split(*excludeFlag, ","))
+	files, err := scan.WalkSourceFiles([]string{srcDir}, supportedExtensions, excludes, *useGitignoreFlag)
 	if err != nil {
 		// If src doesn't exist, the original script might fail or return empty. 
 		// We'll treat a missing directory as empty results to be safe, 
 		// or propagate error if strict. The JS script assumes fs.readdir works.
 		return fmt.Errorf("failed to read src directory: %w", err)
 	}
+	if *changedFlag || *stagedFlag {
+		changedFiles, err := gitChangedFiles(projectRoot, *changedBaseFlag, *stagedFlag)
+		if err != nil {
+			return fmt.Errorf("failed to list changed files: %w", err)
+		}
+		kept := files[:0]
+		for _, file := range files {
+			rel, _ := filepath.Rel(projectRoot, file)
+			if changedFiles[filepath.ToSlash(rel)] {
+				kept = append(kept, file)
+			}
+		}
+		files = kept
+	}
 
 	// 4. Scan Files
-	var allMatches []ConsoleMatch
-	for _, file := range files {
-		matches, err := findMatchesInFile(file, levels)
+	allMatches, lines, err := scanFiles(files, levels, *workersFlag)
+	if err != nil {
+		return err
+	}
+	stats.Files = len(files)
+	stats.Lines = lines
+
+	baselined := 0
+	if *baselineFlag != "" {
+		baselinePath := filepath.Join(projectRoot, *baselineFlag)
+		if *updateBaselineFlag {
+			baseline := scan.NewBaseline()
+			for _, m := range allMatches {
+				baseline.Add(relSlashPath(projectRoot, m.File), m.Content)
+			}
+			if err := scan.WriteBaseline(baselinePath, baseline); err != nil {
+				return fmt.Errorf("failed to write baseline: %w", err)
+			}
+			fmt.Fprintf(logOut, "📝 Baseline %s updated with %d console statements.\n", *baselineFlag, len(allMatches))
+			return nil
+		}
+
+		baseline, err := scan.LoadBaseline(baselinePath)
 		if err != nil {
-			return err
+			return fmt.Errorf("failed to read baseline: %w", err)
 		}
-		allMatches = append(allMatches, matches...)
+		newMatches := allMatches[:0]
+		for _, m := range allMatches {
+			if !baseline.Take(relSlashPath(projectRoot, m.File), m.Content) {
+				newMatches = append(newMatches, m)
+			}
+		}
+		baselined = len(allMatches) - len(newMatches)
+		allMatches = newMatches
+	} else if *updateBaselineFlag {
+		return errors.New("--update-baseline requires --baseline=FILE")
 	}
 
 	// 5. Print Results
-	printResults(allMatches, levels, projectRoot)
+	if *formatFlag == formatJSON {
+		if err := printJSON(allMatches, projectRoot, policy); err != nil {
+			return err
+		}
+	} else {
+		printResults(allMatches, levels, projectRoot)
+	}
+	if baselined > 0 {
+		fmt.Fprintf(logOut, "Baseline: %d known console statements suppressed\n", baselined)
+	}
+	stats.Matches = len(allMatches)
+
+	// 6. Check the --fail-on-match / --max threshold
+	if *failOnMatchFlag && *maxFlag < 0 {
+		*maxFlag = 0
+	}
+	if *maxFlag >= 0 && len(allMatches) > *maxFlag {
+		fmt.Fprintf(os.Stderr, "❌ %d console statements exceeds the allowed maximum of %d\n", len(allMatches), *maxFlag)
+		exitCode = exitFindings
+	}
+	matchLevels := make([]string, len(allMatches))
+	for i, m := range allMatches {
+		matchLevels[i] = m.Level
+	}
+	if count, failed := policy.Failing(matchLevels); failed {
+		fmt.Fprintf(os.Stderr, "❌ %d console statements at %s severity or above exceeds the --fail-on maximum of %d\n", count, policy.FailOn, policy.FailMax)
+		exitCode = exitFindings
+	}
 
 	return nil
 }
@@ -124,12 +292,12 @@ func parseLevelsArg(levelArg, levelsArg string) []string {
 		}
 
 		if len(invalid) > 0 {
-			fmt.Printf("Ignoring invalid level(s) in --levels: %s. Valid values are: %s.\n",
+			fmt.Fprintf(logOut, "Ignoring invalid level(s) in --levels: %s. Valid values are: %s.\n",
 				strings.Join(invalid, ", "), strings.Join(validLevels, ", "))
 		}
 
 		if len(finalLevels) == 0 {
-			fmt.Printf("No valid levels provided to --levels. Falling back to \"%s\".\n", defaultLevel)
+			fmt.Fprintf(logOut, "No valid levels provided to --levels. Falling back to \"%s\".\n", defaultLevel)
 			return []string{defaultLevel}
 		}
 
@@ -140,7 +308,7 @@ func parseLevelsArg(levelArg, levelsArg string) []string {
 	if levelArg != "" {
 		clean := strings.TrimSpace(levelArg)
 		if !validLevelSet[clean] {
-			fmt.Printf("Invalid --level value \"%s\". Valid values are: %s. Falling back to \"%s\".\n",
+			fmt.Fprintf(logOut, "Invalid --level value \"%s\". Valid values are: %s. Falling back to \"%s\".\n",
 				clean, strings.Join(validLevels, ", "), defaultLevel)
 			return []string{defaultLevel}
 		}
@@ -151,50 +319,100 @@ func parseLevelsArg(levelArg, levelsArg string) []string {
 	return []string{defaultLevel}
 }
 
-// collectSourceFiles walks the directory tree recursively
-func collectSourceFiles(rootDir string) ([]string, error) {
-	var results []string
-
-	err := filepath.WalkDir(rootDir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		name := d.Name()
-
-		if d.IsDir() {
-			if excludedDirs[name] {
-				return filepath.SkipDir
-			}
-			return nil
+// gitChangedFiles returns the files changed between base and HEAD, or with
+// staged the files staged for commit, as slash paths relative to projectRoot
+func gitChangedFiles(projectRoot, base string, staged bool) (map[string]bool, error) {
+	revision := base + "...HEAD"
+	if staged {
+		revision = "--cached"
+	}
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", revision, "--")
+	cmd.Dir = projectRoot
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s: %s", revision, strings.TrimSpace(string(exitErr.Stderr)))
 		}
+		return nil, err
+	}
 
-		ext := filepath.Ext(name)
-		if supportedExtensions[ext] {
-			results = append(results, path)
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			changed[filepath.ToSlash(line)] = true
 		}
-
-		return nil
-	})
-
-	return results, err
+	}
+	return changed, nil
 }
 
-// findMatchesInFile scans a single file for regex matches
-func findMatchesInFile(filePath string, levels []string) ([]ConsoleMatch, error) {
-	file, err := os.Open(filePath)
+// relSlashPath returns path relative to root with forward slashes, the form
+// used for baseline keys
+func relSlashPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
 	if err != nil {
-		return nil, err
+		return filepath.ToSlash(path)
 	}
-	defer file.Close()
+	return filepath.ToSlash(rel)
+}
+
+// fileMatches is the outcome of scanning one file
+type fileMatches struct {
+	matches []ConsoleMatch
+	lines   int
+}
 
-	// Pre-compile regexes for performance
+// compileLevelPatterns builds the console.<level>( regex for each level
+func compileLevelPatterns(levels []string) map[string]*regexp.Regexp {
 	patterns := make(map[string]*regexp.Regexp)
 	for _, l := range levels {
 		// regex: \bconsole.<level>\s*\(
 		pattern := fmt.Sprintf(`\bconsole\.%s\s*\(`, regexp.QuoteMeta(l))
 		patterns[l] = regexp.MustCompile(pattern)
 	}
+	return patterns
+}
+
+// scanFiles runs findMatchesInFile on workerCount goroutines and returns the
+// matches ordered by file, then line, and the number of lines read
+func scanFiles(files []string, levels []string, workerCount int) ([]ConsoleMatch, int, error) {
+	// Compiled once and shared read-only by every worker
+	patterns := compileLevelPatterns(levels)
+
+	results, err := scan.Parallel(files, workerCount, func(file string) (fileMatches, error) {
+		matches, lines, err := findMatchesInFile(file, levels, patterns)
+		return fileMatches{matches: matches, lines: lines}, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		allMatches []ConsoleMatch
+		totalLines int
+	)
+	for _, res := range results {
+		allMatches = append(allMatches, res.matches...)
+		totalLines += res.lines
+	}
+
+	// Matches on the same line keep their level order
+	sort.SliceStable(allMatches, func(i, j int) bool {
+		if allMatches[i].File == allMatches[j].File {
+			return allMatches[i].Line < allMatches[j].Line
+		}
+		return allMatches[i].File < allMatches[j].File
+	})
+	return allMatches, totalLines, nil
+}
+
+// findMatchesInFile scans a single file for regex matches, also returning the
+// number of lines read
+func findMatchesInFile(filePath string, levels []string, patterns map[string]*regexp.Regexp) ([]ConsoleMatch, int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
 
 	var matches []ConsoleMatch
 	scanner := bufio.NewScanner(file)
@@ -217,7 +435,7 @@ func findMatchesInFile(filePath string, levels []string) ([]ConsoleMatch, error)
 		}
 	}
 
-	return matches, scanner.Err()
+	return matches, lineNum, scanner.Err()
 }
 
 // printResults dispatches to single or multi level printers
@@ -241,7 +459,8 @@ func printSingleLevelResults(matches []ConsoleMatch, level string, root string)
 		grouped[rel] = append(grouped[rel], m)
 	}
 
-	fmt.Printf("console.%s usage:\n\n", level)
+	fmt.Println(scan.Colorize(fmt.Sprintf("console.%s usage:", level), scan.AnsiBold) + "\n")
+	pattern := compileLevelPatterns([]string{level})[level]
 
 	// Sort files for deterministic output
 	var files []string
@@ -251,7 +470,7 @@ func printSingleLevelResults(matches []ConsoleMatch, level string, root string)
 	sort.Strings(files)
 
 	for _, f := range files {
-		fmt.Printf("\n%s\n", f)
+		fmt.Printf("\n%s\n", scan.Colorize(f, scan.AnsiCyan))
 		entries := grouped[f]
 		// Sort by line number
 		sort.Slice(entries, func(i, j int) bool {
@@ -259,7 +478,7 @@ func printSingleLevelResults(matches []ConsoleMatch, level string, root string)
 		})
 
 		for _, e := range entries {
-			fmt.Printf("  %s (line %d)\n", e.Content, e.Line)
+			fmt.Printf("  %s (line %d)\n", scan.Highlight(e.Content, pattern, levelStyle(level)), e.Line)
 		}
 	}
 
@@ -276,7 +495,8 @@ func printMultiLevelResults(matches []ConsoleMatch, levels []string, root string
 		return
 	}
 
-	fmt.Printf("console.%s usage (grouped by level):\n", strings.Join(levels, ", "))
+	fmt.Println(scan.Colorize(fmt.Sprintf("console.%s usage (grouped by level):", strings.Join(levels, ", ")), scan.AnsiBold))
+	patterns := compileLevelPatterns(levels)
 
 	matchesByLevel := make(map[string][]ConsoleMatch)
 	for _, l := range levels {
@@ -297,7 +517,7 @@ func printMultiLevelResults(matches []ConsoleMatch, levels []string, root string
 	for _, l := range levels {
 		levelMatches := matchesByLevel[l]
 		if len(levelMatches) == 0 {
-			fmt.Printf("\n=== console.%s ===\n", l)
+			fmt.Printf("\n%s\n", scan.Colorize(fmt.Sprintf("=== console.%s ===", l), scan.AnsiBold))
 			fmt.Println("  (no matches)")
 			summaries = append(summaries, summary{Level: l, Count: 0, FileCount: 0})
 			continue
@@ -316,16 +536,16 @@ func printMultiLevelResults(matches []ConsoleMatch, levels []string, root string
 		}
 		sort.Strings(files)
 
-		fmt.Printf("\n=== console.%s ===\n", l)
+		fmt.Printf("\n%s\n", scan.Colorize(fmt.Sprintf("=== console.%s ===", l), scan.AnsiBold))
 
 		for _, f := range files {
-			fmt.Printf("\n%s\n", f)
+			fmt.Printf("\n%s\n", scan.Colorize(f, scan.AnsiCyan))
 			entries := grouped[f]
 			sort.Slice(entries, func(i, j int) bool {
 				return entries[i].Line < entries[j].Line
 			})
 			for _, e := range entries {
-				fmt.Printf("  %s (line %d)\n", e.Content, e.Line)
+				fmt.Printf("  %s (line %d)\n", scan.Highlight(e.Content, patterns[l], levelStyle(l)), e.Line)
 			}
 		}
 
@@ -336,9 +556,44 @@ func printMultiLevelResults(matches []ConsoleMatch, levels []string, root string
 		})
 	}
 
-	fmt.Println("\nSummary:")
+	fmt.Println("\n" + scan.Colorize("Summary:", scan.AnsiBold))
 	for _, s := range summaries {
 		fmt.Printf("  console.%s: %d statement(s) in %d file(s)\n", s.Level, s.Count, s.FileCount)
 	}
 	fmt.Printf("  Total: %d statement(s) across %d file(s)\n", len(matches), len(totalFiles))
-}
\ No newline at end of file
+}
+
+// levelStyle is the color of a console call's level in the text report
+func levelStyle(level string) string {
+	if level == "error" {
+		return scan.AnsiRed
+	}
+	return scan.AnsiYellow
+}
+
+// printJSON writes every match as a finding with the severity of its level,
+// ordered by file then line
+func printJSON(matches []ConsoleMatch, root string, policy scan.SeverityPolicy) error {
+	report := jsonReport{Tool: "console", Findings: make([]jsonFinding, 0, len(matches))}
+	for _, m := range matches {
+		rel, _ := filepath.Rel(root, m.File)
+		report.Findings = append(report.Findings, jsonFinding{
+			Tool:     "console",
+			File:     filepath.ToSlash(rel),
+			Line:     m.Line,
+			Severity: policy.Severity(m.Level),
+			Message:  m.Content,
+			Kind:     m.Level,
+		})
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		if report.Findings[i].File == report.Findings[j].File {
+			return report.Findings[i].Line < report.Findings[j].Line
+		}
+		return report.Findings[i].File < report.Findings[j].File
+	})
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
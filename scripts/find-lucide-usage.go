@@ -1,14 +1,26 @@
+//go:build ignore
+
 package main
 
 import (
 	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
 )
 
 // LucideMatch represents a finding of the search token in a file.
@@ -16,88 +28,1127 @@ type LucideMatch struct {
 	File    string
 	Line    int
 	Content string
+	// Token lists the search tokens the line contains, comma-separated
+	Token string
+	// Snippet holds the surrounding lines when --context is set
+	Snippet []string
+}
+
+// IconImport is one Lucide icon brought into a file by an import statement.
+type IconImport struct {
+	File string
+	Line int
+	// Icon is the exported icon name, e.g. ChartColumn
+	Icon string
+	// Local is the identifier the file binds it to, e.g. BarChart3
+	Local  string
+	Source string
+	// Uses counts references to Local outside imports and comments
+	Uses int
+	// UseLines holds the line of each reference counted in Uses
+	UseLines []int
+}
+
+// DynamicUsage is a place where Lucide icons are looked up or imported as a
+// whole at runtime, which defeats tree-shaking and can pull the entire icon
+// set into the bundle.
+type DynamicUsage struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Content string `json:"content"`
+	Reason  string `json:"reason"`
+}
+
+// fileScan is everything scanFile finds in one file.
+type fileScan struct {
+	matches []LucideMatch
+	imports []IconImport
+	dynamic []DynamicUsage
+	lines   int
+}
+
+// scanOptions controls what scanFile reports.
+type scanOptions struct {
+	// importsOnly reports Lucide import statements instead of every line
+	// containing the match token
+	importsOnly bool
+	// sources restricts matches to these packages; empty allows any Lucide package
+	sources []string
+	// tokens are the lowercase substrings a line must contain to match
+	tokens []string
+	// context is the number of lines shown around each match
+	context int
+}
+
+// stringSlice handles comma-separated flags or multiple flag occurrences
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	parts := strings.Split(value, ",")
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			*s = append(*s, trimmed)
+		}
+	}
+	return nil
+}
+
+// jsonMatch is one matched line in the --format=json report.
+type jsonMatch struct {
+	Line    int      `json:"line"`
+	Content string   `json:"content"`
+	Token   string   `json:"token,omitempty"`
+	Snippet []string `json:"snippet,omitempty"`
+}
+
+// jsonImport is one parsed icon import in the --format=json report.
+type jsonImport struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Icon     string `json:"icon"`
+	Local    string `json:"local"`
+	Source   string `json:"source"`
+	Uses     int    `json:"uses"`
+	UseLines []int  `json:"useLines,omitempty"`
+}
+
+// jsonReport is the document written by --format=json.
+type jsonReport struct {
+	Files        map[string][]jsonMatch `json:"files"`
+	TotalFiles   int                    `json:"totalFiles"`
+	TotalMatches int                    `json:"totalMatches"`
+	// IconUses maps each imported icon to its reference count across all files
+	IconUses map[string]int `json:"iconUses"`
+	Imports  []jsonImport   `json:"imports"`
+	// UnknownIcons lists imports of icons missing from the --allowed set
+	UnknownIcons []jsonImport `json:"unknownIcons,omitempty"`
+	// Size is set when --icon-size-bytes or --icon-sizes is given
+	Size *sizeEstimate `json:"sizeEstimate,omitempty"`
+	// DynamicUsage lists runtime lookups that can bundle every icon
+	DynamicUsage []DynamicUsage `json:"dynamicUsage"`
+	// DeprecatedIcons is set when --deprecated is given
+	DeprecatedIcons []DeprecatedIcon `json:"deprecatedIcons,omitempty"`
+	// Severity maps each finding kind to its --severity level
+	Severity map[string]string `json:"severity"`
+}
+
+// DeprecatedIcon is an import of an icon name listed in the --deprecated map.
+type DeprecatedIcon struct {
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Icon        string `json:"icon"`
+	Replacement string `json:"replacement"`
+}
+
+// sizeEstimate approximates the bundle bytes contributed by the distinct
+// icons imported, one module per icon.
+type sizeEstimate struct {
+	DistinctIcons int            `json:"distinctIcons"`
+	TotalBytes    int            `json:"totalBytes"`
+	IconBytes     map[string]int `json:"iconBytes"`
+}
+
+// iconAnalysis holds the results of the optional checks run over the parsed
+// imports.
+type iconAnalysis struct {
+	// unknown lists imports of icons missing from the --allowed set
+	unknown []IconImport
+	// size is the --icon-size-bytes / --icon-sizes payload estimate
+	size *sizeEstimate
+	// dynamic lists runtime icon lookups and whole-set imports
+	dynamic []DynamicUsage
+	// deprecated lists imports of renamed icons (--deprecated)
+	deprecated []DeprecatedIcon
+}
+
+// Exit codes shared by every scanner
+const (
+	exitFindings = 1 // findings over a --fail-on-* / --max threshold
+	exitError    = 2 // invalid usage or a runtime/I/O error
+)
+
+// Finding kinds, for --severity
+const (
+	kindIconImport   = "icon-import"
+	kindUnknownIcon  = "unknown-icon"
+	kindDeprecated   = "deprecated-icon"
+	kindDynamicUsage = "dynamic-icon-usage"
+)
+
+// defaultSeverities are the levels of the finding kinds without --severity
+var defaultSeverities = map[string]string{
+	kindIconImport:   scan.SeverityInfo,
+	kindUnknownIcon:  scan.SeverityError,
+	kindDeprecated:   scan.SeverityWarning,
+	kindDynamicUsage: scan.SeverityWarning,
 }
 
+// Output formats accepted by --format
+const (
+	formatText     = "text"
+	formatJSON     = "json"
+	formatCSV      = "csv"
+	formatMarkdown = "markdown"
+)
+
+// Groupings accepted by --group-by
+const (
+	groupByFile = "file"
+	groupByIcon = "icon"
+)
+
+// matchToken is the default search token; --token replaces it
 const matchToken = "lucide"
 
+// lucideSource matches the module specifiers Lucide packages are imported from:
+// lucide, lucide-react, lucide-vue-next, @lucide/svelte, and their subpaths.
+const lucideSource = `(?:lucide(?:-[a-z]+)*|@lucide/[a-z-]+)(?:/[^'"]*)?`
+
+var (
+	// namedImportRegex matches `import { A, B as C } from 'lucide-react'`,
+	// including imports whose specifier list spans several lines.
+	namedImportRegex = regexp.MustCompile(`(?m)^[ \t]*import\s+(type\s+)?(?:[A-Za-z_$][\w$]*\s*,\s*)?\{([^}]*)\}\s*from\s*['"](` + lucideSource + `)['"]`)
+	// iconModuleImportRegex matches per-icon default imports such as
+	// `import BarChart3 from 'lucide/dist/esm/icons/chart-column.js'` or
+	// `import House from 'lucide-react/icons/house'`.
+	iconModuleImportRegex = regexp.MustCompile(`(?m)^[ \t]*import\s+([A-Za-z_$][\w$]*)\s+from\s*['"]((?:lucide(?:-[a-z]+)*|@lucide/[a-z-]+)(?:/dist/[^'"]*)?/icons/([a-z0-9-]+)(?:\.[cm]?js)?)['"]`)
+	importSpecifierRegex  = regexp.MustCompile(`(type\s+)?([A-Za-z_$][\w$]*)(?:\s+as\s+([A-Za-z_$][\w$]*))?`)
+	commentRegex          = regexp.MustCompile(`(?s)//[^\n]*|/\*.*?\*/`)
+	// importStatementRegex matches any static import or re-export from a
+	// Lucide package, plus require() and dynamic import() calls.
+	importStatementRegex = regexp.MustCompile(`(?m)(?:^[ \t]*(?:import|export)\s+(?:[^'";]*?from\s*)?|\b(?:require|import)\s*\(\s*)['"](` + lucideSource + `)['"]`)
+	whitespaceRegex      = regexp.MustCompile(`\s+`)
+	scriptOpenRegex      = regexp.MustCompile(`(?i)<script\b[^>]*>`)
+	scriptCloseRegex     = regexp.MustCompile(`(?i)</script\s*>`)
+	// namespaceImportRegex matches `import * as Icons from 'lucide-react'`.
+	namespaceImportRegex = regexp.MustCompile(`(?m)^[ \t]*import\s+\*\s+as\s+([A-Za-z_$][\w$]*)\s+from\s*['"](` + lucideSource + `)['"]`)
+	// dynamicModuleImportRegex matches the default export of the lazy-import
+	// map, e.g. `import dynamicIconImports from 'lucide-react/dynamicIconImports'`.
+	dynamicModuleImportRegex = regexp.MustCompile(`(?m)^[ \t]*import\s+([A-Za-z_$][\w$]*)\s+from\s*['"]((?:lucide(?:-[a-z]+)*|@lucide/[a-z-]+)/dynamic(?:IconImports)?(?:\.[cm]?js)?)['"]`)
+	// globalRegistryRegex matches lookups in the global runtime's registry,
+	// e.g. window.lucide.icons[name] or lucide?.icons?.[name].
+	globalRegistryRegex = regexp.MustCompile(`\blucide\s*\??\.\s*icons\s*(?:\?\.)?\s*\[`)
+)
+
+// registryExports are Lucide exports holding every icon; importing them or
+// indexing them with a runtime key bundles the full set.
+var registryExports = map[string]bool{
+	"icons":              true,
+	"dynamicIconImports": true,
+}
+
+// nonIconExports are PascalCase exports of the Lucide packages that are
+// types or helpers rather than icons.
+var nonIconExports = map[string]bool{
+	"Icon":           true,
+	"IconNode":       true,
+	"IconProps":      true,
+	"LucideIcon":     true,
+	"LucideIconNode": true,
+	"LucideProps":    true,
+	"SVGProps":       true,
+}
+
 // supportedExtensions acts as a set for O(1) lookups.
 var supportedExtensions = map[string]bool{
-	".ts":  true,
-	".tsx": true,
-	".js":  true,
-	".jsx": true,
+	".ts":     true,
+	".tsx":    true,
+	".js":     true,
+	".jsx":    true,
+	".vue":    true,
+	".svelte": true,
 }
 
+// componentExtensions are single-file component formats; only their
+// <script> blocks are scanned, template and style sections are skipped
+var componentExtensions = map[string]bool{
+	".vue":    true,
+	".svelte": true,
+}
+
+// exitCode is set by run when a policy check such as --fail-on-unknown fails;
+// errors returned by run exit with exitError instead
+var exitCode int
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error scanning for Lucide references: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
+	os.Exit(exitCode)
 }
 
 func run() error {
 	// 2a, 2b: Execution timing wrapping the primary logic
 	startTime := time.Now()
+	// Informational lines go to stderr when stdout carries a machine-readable
+	// report or the report is written to --out
+	out := io.Writer(os.Stdout)
+	var logOut io.Writer = os.Stdout
+	var quiet, showStats bool
+	var stats scan.Stats
 	defer func() {
 		// 2c: Formatted timing output
-		fmt.Printf("\nTotal execution time: %v\n", time.Since(startTime))
+		if !quiet {
+			fmt.Fprintf(logOut, "\nTotal execution time: %v\n", time.Since(startTime))
+			if showStats {
+				stats.Elapsed = time.Since(startTime)
+				scan.PrintStats(logOut, "lucide", stats)
+			}
+		}
 	}()
 
 	// 1c: Argument parsing using 'flag'
 	// Defaults to "src" to maintain 1:1 behavior with original script which hardcoded 'src'
-	targetDir := flag.String("dir", "src", "Directory to scan")
+	var dirFlags stringSlice
+	flag.Var(&dirFlags, "dir", "Directory to scan; globs such as packages/*/src are expanded (repeatable or comma-separated; default src)")
+	var opts scanOptions
+	var sources stringSlice
+	var tokens stringSlice
+	flag.BoolVar(&opts.importsOnly, "imports-only", false, "Only report import statements from Lucide packages, not every line mentioning lucide")
+	flag.Var(&sources, "source", "Only consider imports from these packages, e.g. lucide-react (repeatable or comma-separated)")
+	flag.Var(&tokens, "token", "Case-insensitive substring to search for (repeatable or comma-separated; default lucide)")
+	flag.IntVar(&opts.context, "context", 0, "Number of lines of context to show around each match")
+	format := flag.String("format", formatText, "Output format: text, json, csv, or markdown")
+	failOnMatch := flag.Bool("fail-on-match", false, "Exit with code 1 when any match is reported (same as --max=0)")
+	maxMatches := flag.Int("max", -1, "Exit with code 1 when more than N matches are reported (-1 disables the check)")
+	maxIcons := flag.Int("max-icons", 0, "Exit with code 1 when more than N distinct icons are imported (0 disables the check)")
+	workers := flag.Int("workers", runtime.NumCPU(), "Number of worker goroutines scanning files in parallel")
+	groupBy := flag.String("group-by", groupByFile, "Group the text report by file or by icon (icon lists every import and use, most used first)")
+	var exclude stringSlice
+	flag.Var(&exclude, "exclude", "Skip files matching these globs, e.g. **/*.spec.ts; a bare name such as fixtures skips directories with that name, in addition to node_modules, dist, and the other defaults (repeatable or comma-separated)")
+	useGitignore := flag.Bool("use-gitignore", false, "Skip files ignored by git (.gitignore, .git/info/exclude, global excludes)")
+	allowedPath := flag.String("allowed", "", "File listing approved icon names, one per line (PascalCase or kebab-case; # starts a comment)")
+	failOnUnknown := flag.Bool("fail-on-unknown", false, "Exit with code 1 when an icon outside the --allowed set is imported")
+	severityFlag := flag.String("severity", "", "Severity per finding kind as kind=error|warning|info, e.g. deprecated-icon=error (kinds: icon-import, unknown-icon, deprecated-icon, dynamic-icon-usage)")
+	failOn := flag.String("fail-on", "", "Exit with code 1 when more than N icon findings (default 0) are at LEVEL severity or above, as LEVEL[:N]")
+	iconSizeBytes := flag.Int("icon-size-bytes", 0, "Estimate the icon payload assuming each distinct icon adds this many bytes")
+	deprecatedPath := flag.String("deprecated", "", "JSON file mapping deprecated icon names to their replacements")
+	iconSizesPath := flag.String("icon-sizes", "", "JSON file mapping icon names to byte sizes for the payload estimate; unlisted icons use --icon-size-bytes")
+	outPath := flag.String("out", "", "Write the report to this file instead of stdout; informational lines go to stderr")
+	flag.BoolVar(&quiet, "quiet", false, "Suppress the execution time trailer and the --stats footer")
+	flag.BoolVar(&showStats, "stats", false, "Print a footer with files scanned, lines inspected, matches found, and elapsed time")
+	baselinePath := flag.String("baseline", "", "Baseline JSON of known references keyed by file and line text (e.g. lucide-baseline.json); only new ones are reported and counted by --max")
+	updateBaseline := flag.Bool("update-baseline", false, "Rewrite the --baseline file with the current references and exit")
+	colorMode := flag.String("color", scan.ColorAuto, "Color the text report on stdout: auto (terminal without NO_COLOR), always, or never")
+	stdinPaths := flag.Bool("stdin-paths", false, "Scan the newline-delimited file paths read from stdin instead of walking --dir")
+	changed := flag.Bool("changed", false, "Only scan files changed on this branch (git diff --name-only <base>...HEAD)")
+	changedBase := flag.String("changed-base", "origin/main", "Base ref used by --changed")
+	staged := flag.Bool("staged", false, "Only scan files staged for commit (git diff --cached --name-only), e.g. from a pre-commit hook")
 	flag.Parse()
 
+	if *workers <= 0 {
+		*workers = runtime.NumCPU()
+	}
+	if *groupBy != groupByFile && *groupBy != groupByIcon {
+		return fmt.Errorf("invalid value for --group-by: %s (expected file or icon)", *groupBy)
+	}
+	if opts.context < 0 {
+		return fmt.Errorf("invalid value for --context: %d", opts.context)
+	}
+	if *iconSizeBytes < 0 {
+		return fmt.Errorf("invalid value for --icon-size-bytes: %d", *iconSizeBytes)
+	}
+	var renames map[string]string
+	if *deprecatedPath != "" {
+		var err error
+		if renames, err = loadDeprecatedIcons(*deprecatedPath); err != nil {
+			return fmt.Errorf("failed to load --deprecated map: %w", err)
+		}
+	}
+	var iconSizes map[string]int
+	if *iconSizesPath != "" {
+		var err error
+		if iconSizes, err = loadIconSizes(*iconSizesPath); err != nil {
+			return fmt.Errorf("failed to load --icon-sizes: %w", err)
+		}
+	}
+
+	if *failOnUnknown && *allowedPath == "" {
+		return errors.New("--fail-on-unknown requires --allowed")
+	}
+	policy, err := scan.NewSeverityPolicy(*severityFlag, *failOn, defaultSeverities)
+	if err != nil {
+		return err
+	}
+	var allowed map[string]bool
+	if *allowedPath != "" {
+		var err error
+		if allowed, err = loadAllowedIcons(*allowedPath); err != nil {
+			return fmt.Errorf("failed to load --allowed list: %w", err)
+		}
+	}
+
+	switch *format {
+	case formatText:
+	case formatJSON, formatCSV, formatMarkdown:
+		logOut = os.Stderr
+	default:
+		return fmt.Errorf("invalid value for --format: %s (expected text, json, csv, or markdown)", *format)
+	}
+	if *stdinPaths && len(dirFlags) > 0 {
+		return errors.New("--dir cannot be combined with --stdin-paths")
+	}
+	if *updateBaseline && *baselinePath == "" {
+		return errors.New("--update-baseline requires --baseline=FILE")
+	}
+	if *outPath != "" {
+		outFile, err := os.Create(*outPath)
+		if err != nil {
+			return fmt.Errorf("failed to create --out file: %w", err)
+		}
+		defer outFile.Close()
+		out = outFile
+		logOut = os.Stderr
+	}
+	enabled, err := scan.ColorEnabled(*colorMode, os.Stdout)
+	if err != nil {
+		return err
+	}
+	scan.SetColor(enabled && *format == formatText && *outPath == "")
+	opts.sources = sources
+	if len(tokens) == 0 {
+		tokens = stringSlice{matchToken}
+	}
+	for _, token := range tokens {
+		opts.tokens = append(opts.tokens, strings.ToLower(token))
+	}
+	multiToken := len(opts.tokens) > 1
+
+	// Keep the original wording for the default token
+	subject := "Lucide"
+	heading := "Lucide icon references:"
+	if multiToken || opts.tokens[0] != matchToken {
+		subject = strings.Join(opts.tokens, ", ")
+		heading = fmt.Sprintf("References to %s:", subject)
+	}
+
 	projectRoot, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current working directory: %w", err)
 	}
 
-	searchPath := filepath.Join(projectRoot, *targetDir)
-
-	// Verify directory exists
-	info, err := os.Stat(searchPath)
-	if err != nil || !info.IsDir() {
-		return fmt.Errorf("directory not found: %s", searchPath)
+	var changedFiles map[string]bool
+	if *changed || *staged {
+		changedFiles, err = gitChangedFiles(projectRoot, *changedBase, *staged)
+		if err != nil {
+			return fmt.Errorf("failed to list changed files: %w", err)
+		}
 	}
 
-	var allMatches []LucideMatch
+	excludes := scan.NewExcludes(exclude)
+	var files []string
+	if *stdinPaths {
+		files, err = readPathList(os.Stdin, projectRoot, excludes, changedFiles)
+		if err == nil && *useGitignore {
+			files, err = scan.KeepGitFiles(projectRoot, files)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read paths from stdin: %w", err)
+		}
+	} else {
+		if len(dirFlags) == 0 {
+			dirFlags = stringSlice{"src"}
+		}
+
+		var searchPaths []string
+		for _, dir := range dirFlags {
+			pattern := filepath.Join(projectRoot, dir)
+			matches, err := filepath.Glob(pattern)
+			if err != nil || len(matches) == 0 {
+				// Reported below as a missing directory
+				searchPaths = append(searchPaths, pattern)
+				continue
+			}
+			for _, match := range matches {
+				// A glob like packages/* may also match plain files
+				if info, err := os.Stat(match); err == nil && info.IsDir() {
+					searchPaths = append(searchPaths, match)
+				}
+			}
+		}
+
+		for _, searchPath := range searchPaths {
+			// Verify directory exists
+			info, err := os.Stat(searchPath)
+			if err != nil || !info.IsDir() {
+				return fmt.Errorf("directory not found: %s", searchPath)
+			}
+		}
 
-	// 1b: Use standard library filepath.WalkDir instead of manual recursion
-	err = filepath.WalkDir(searchPath, func(path string, d os.DirEntry, err error) error {
+		// Overlapping directories are walked once per file
+		walked, err := scan.WalkSourceFiles(searchPaths, supportedExtensions, excludes, *useGitignore)
 		if err != nil {
 			return err
 		}
+		for _, path := range walked {
+			rel, _ := filepath.Rel(projectRoot, path)
+			if changedFiles == nil || changedFiles[filepath.ToSlash(rel)] {
+				files = append(files, path)
+			}
+		}
+	}
 
-		if !d.IsDir() {
-			ext := filepath.Ext(d.Name())
-			if supportedExtensions[ext] {
-				matches, err := scanFile(path, projectRoot)
-				if err != nil {
-					return fmt.Errorf("failed to read file %s: %w", path, err)
-				}
-				allMatches = append(allMatches, matches...)
+	var allMatches []LucideMatch
+	var allImports []IconImport
+	var analysis iconAnalysis
+	results, err := scanFiles(files, projectRoot, opts, *workers)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		allMatches = append(allMatches, result.matches...)
+		allImports = append(allImports, result.imports...)
+		analysis.dynamic = append(analysis.dynamic, result.dynamic...)
+		stats.Lines += result.lines
+	}
+	stats.Files = len(files)
+
+	// The baseline covers the reported references; icon summaries and checks
+	// still see every import
+	baselined := 0
+	if *baselinePath != "" {
+		baselineFile := filepath.Join(projectRoot, *baselinePath)
+		if *updateBaseline {
+			baseline := scan.NewBaseline()
+			for _, match := range allMatches {
+				baseline.Add(match.File, match.Content)
+			}
+			if err := scan.WriteBaseline(baselineFile, baseline); err != nil {
+				return fmt.Errorf("failed to write baseline: %w", err)
 			}
+			fmt.Fprintf(logOut, "📝 Baseline %s updated with %d references.\n", *baselinePath, len(allMatches))
+			return nil
 		}
-		return nil
-	})
 
+		baseline, err := scan.LoadBaseline(baselineFile)
+		if err != nil {
+			return fmt.Errorf("failed to read baseline: %w", err)
+		}
+		newMatches := allMatches[:0]
+		for _, match := range allMatches {
+			if !baseline.Take(match.File, match.Content) {
+				newMatches = append(newMatches, match)
+			}
+		}
+		baselined = len(allMatches) - len(newMatches)
+		allMatches = newMatches
+	}
+	stats.Matches = len(allMatches)
+
+	if allowed != nil {
+		analysis.unknown = findUnknownIcons(allImports, allowed)
+		if *failOnUnknown && len(analysis.unknown) > 0 {
+			exitCode = exitFindings
+		}
+	}
+
+	if renames != nil {
+		analysis.deprecated = findDeprecatedIcons(allImports, renames)
+	}
+	if *iconSizeBytes > 0 || iconSizes != nil {
+		analysis.size = estimateIconPayload(allImports, *iconSizeBytes, iconSizes)
+	}
+
+	switch *format {
+	case formatJSON:
+		err = printJSON(out, allMatches, allImports, analysis, multiToken, policy)
+	case formatCSV:
+		err = printCSV(out, allImports)
+	case formatMarkdown:
+		printMarkdown(out, allImports)
+	default:
+		printTextReport(out, allMatches, allImports, analysis, textOptions{
+			heading:         heading,
+			subject:         subject,
+			tokens:          opts.tokens,
+			groupBy:         *groupBy,
+			checkAllowed:    allowed != nil,
+			checkDeprecated: renames != nil,
+		})
+	}
 	if err != nil {
 		return err
 	}
+	if baselined > 0 {
+		fmt.Fprintf(logOut, "Baseline: %d known references suppressed\n", baselined)
+	}
+
+	// Checked after the report so the failure message follows it
+	if *maxIcons > 0 && !checkMaxIcons(logOut, allImports, *maxIcons) {
+		exitCode = exitFindings
+	}
+	if *failOnMatch && *maxMatches < 0 {
+		*maxMatches = 0
+	}
+	if *maxMatches >= 0 && len(allMatches) > *maxMatches {
+		fmt.Fprintf(logOut, "\n❌ %d %s matches, over the --max limit of %d.\n", len(allMatches), subject, *maxMatches)
+		exitCode = exitFindings
+	}
+	if count, failed := policy.Failing(analysis.kinds(allImports)); failed {
+		fmt.Fprintf(logOut, "\n❌ %d icon findings at %s severity or above exceeds the --fail-on maximum of %d.\n", count, policy.FailOn, policy.FailMax)
+		exitCode = exitFindings
+	}
+	return nil
+}
+
+// kinds lists the kind of every icon finding: each import, then the unknown,
+// deprecated, and dynamic usages found by the analysis
+func (a iconAnalysis) kinds(imports []IconImport) []string {
+	kinds := make([]string, 0, len(imports)+len(a.unknown)+len(a.deprecated)+len(a.dynamic))
+	for range imports {
+		kinds = append(kinds, kindIconImport)
+	}
+	for range a.unknown {
+		kinds = append(kinds, kindUnknownIcon)
+	}
+	for range a.deprecated {
+		kinds = append(kinds, kindDeprecated)
+	}
+	for range a.dynamic {
+		kinds = append(kinds, kindDynamicUsage)
+	}
+	return kinds
+}
+
+// textOptions carries the settings that shape the text report.
+type textOptions struct {
+	heading string
+	subject string
+	tokens  []string
+	groupBy string
+	// checkAllowed and checkDeprecated report the --allowed and
+	// --deprecated results even when nothing was flagged
+	checkAllowed    bool
+	checkDeprecated bool
+}
 
+// printTextReport prints the human-readable report: the matches grouped by
+// file or icon, the totals, and every icon summary. The summaries are printed
+// even when no references were found, so an unknown or deprecated icon that
+// fails the run is always listed.
+func printTextReport(w io.Writer, allMatches []LucideMatch, allImports []IconImport, analysis iconAnalysis, text textOptions) {
 	if len(allMatches) == 0 {
-		fmt.Println("No Lucide references found.")
-		return nil
+		fmt.Fprintf(w, "No %s references found.\n", text.subject)
+	} else {
+		printMatches(w, allMatches, allImports, text)
 	}
 
+	printIconSet(w, allImports)
+	printTopIcons(w, allImports)
+	printUnusedImports(w, allImports)
+	printDynamicUsage(w, analysis.dynamic)
+	if text.checkAllowed {
+		printUnknownIcons(w, analysis.unknown)
+	}
+	if text.checkDeprecated {
+		printDeprecatedIcons(w, analysis.deprecated)
+	}
+	if analysis.size != nil {
+		printSizeEstimate(w, *analysis.size)
+	}
+}
+
+// printMatches prints the matches grouped by file or icon, followed by the
+// totals.
+func printMatches(w io.Writer, allMatches []LucideMatch, allImports []IconImport, text textOptions) {
+	multiToken := len(text.tokens) > 1
+
 	// Group matches by file
 	grouped := make(map[string][]LucideMatch)
 	for _, match := range allMatches {
 		grouped[match.File] = append(grouped[match.File], match)
 	}
+	importsByFile := make(map[string][]IconImport)
+	for _, imp := range allImports {
+		importsByFile[imp.File] = append(importsByFile[imp.File], imp)
+	}
+
+	if text.groupBy == groupByIcon {
+		printByIcon(w, allImports)
+	} else {
+		quoted := make([]string, len(text.tokens))
+		for i, token := range text.tokens {
+			quoted[i] = regexp.QuoteMeta(token)
+		}
+		tokenPattern := regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+		printByFile(w, grouped, importsByFile, text.heading, multiToken, tokenPattern)
+	}
+
+	fmt.Fprintf(w, "\nTotal: %d files with %s references (%d matches).\n", len(grouped), text.subject, len(allMatches))
+	if multiToken {
+		printTokenCounts(w, allMatches, text.tokens)
+	}
+}
+
+// readPathList reads newline-delimited file paths (relative to projectRoot or
+// absolute) for --stdin-paths, keeping the existing files that a directory
+// walk would scan: a supported extension, not excluded and, when changedFiles
+// is non-nil, listed in it (--changed). Paths that no longer exist, such as
+// deletions from git diff --name-only, are skipped with a note on stderr.
+func readPathList(r io.Reader, projectRoot string, excludes scan.Excludes, changedFiles map[string]bool) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		path := strings.TrimSpace(scanner.Text())
+		if path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+		path = filepath.Clean(path)
+		if seen[path] || !supportedExtensions[strings.ToLower(filepath.Ext(path))] {
+			continue
+		}
+		seen[path] = true
+
+		rel, err := filepath.Rel(projectRoot, path)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		if excludes.Match(rel) || (changedFiles != nil && !changedFiles[rel]) {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+			fmt.Fprintf(os.Stderr, "Skipping missing file: %s\n", rel)
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, scanner.Err()
+}
 
-	fmt.Println("Lucide icon references:")
+// scanFiles runs scanFile over paths on a pool of workers. Results keep the
+// order of paths so the report is deterministic.
+func scanFiles(paths []string, projectRoot string, opts scanOptions, workerCount int) ([]fileScan, error) {
+	return scan.Parallel(paths, workerCount, func(path string) (fileScan, error) {
+		result, err := scanFile(path, projectRoot, opts)
+		if err != nil {
+			return result, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		return result, nil
+	})
+}
+
+// scanFile reads a file, finds lines containing the match token (or, with
+// importsOnly, the Lucide import statements), and parses the Lucide icons it
+// imports.
+func scanFile(absPath, projectRoot string, opts scanOptions) (fileScan, error) {
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return fileScan{}, err
+	}
+
+	// Replicates path.relative logic
+	relPath, err := filepath.Rel(projectRoot, absPath)
+	if err != nil {
+		relPath = absPath // Fallback if rel path fails
+	}
+	// Replicates .replace(/\\/g, '/') for consistent output style
+	relPath = filepath.ToSlash(relPath)
+
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	// Snippets and use counts see the whole component, since icons are
+	// referenced from the template; everything else sees only the scripts
+	markup := content
+	original := strings.Split(content, "\n")
+	if componentExtensions[strings.ToLower(filepath.Ext(absPath))] {
+		content = scriptSections(content)
+	}
+	lines := strings.Split(content, "\n")
+	var matches []LucideMatch
+	if opts.importsOnly {
+		matches = findImportStatements(content, relPath, opts.sources)
+	} else {
+		for i, lineContent := range lines {
+			// Case-insensitive check
+			lower := strings.ToLower(lineContent)
+			var hits []string
+			for _, token := range opts.tokens {
+				if strings.Contains(lower, token) {
+					hits = append(hits, token)
+				}
+			}
+			if len(hits) > 0 {
+				matches = append(matches, LucideMatch{
+					File:    relPath,
+					Line:    i + 1,
+					Content: strings.TrimSpace(lineContent),
+					Token:   strings.Join(hits, ", "),
+				})
+			}
+		}
+	}
+
+	if opts.context > 0 {
+		for i := range matches {
+			matches[i].Snippet = createSnippet(original, matches[i].Line-1, opts.context)
+		}
+	}
+
+	result := fileScan{matches: matches, lines: strings.Count(strings.TrimSuffix(markup, "\n"), "\n") + 1}
+	// Every import and registry pattern names a lucide package or
+	// dynamicIconImports, so most files can skip the regex passes
+	if !strings.Contains(content, "lucide") && !strings.Contains(content, "dynamicIconImports") {
+		return result, nil
+	}
+
+	imports := parseIconImports(content, relPath)
+	if len(opts.sources) > 0 {
+		kept := imports[:0]
+		for _, imp := range imports {
+			if sourceAllowed(imp.Source, opts.sources) {
+				kept = append(kept, imp)
+			}
+		}
+		imports = kept
+	}
+	countIconUses(markup, imports)
+	result.imports = imports
+	result.dynamic = findDynamicUsage(content, relPath, opts.sources)
+	return result, nil
+}
+
+// findDynamicUsage reports imports of the whole icon registry (icons,
+// dynamicIconImports, or a namespace import) and lookups in it with a runtime
+// key, such as icons[name] or dynamicIconImports[name]().
+func findDynamicUsage(content, relPath string, sources []string) []DynamicUsage {
+	code := blankComments(content)
+	lines := strings.Split(content, "\n")
+	var usages []DynamicUsage
+	add := func(offset int, reason string) {
+		line := lineAt(content, offset)
+		usages = append(usages, DynamicUsage{
+			File:    relPath,
+			Line:    line,
+			Content: strings.TrimSpace(lines[line-1]),
+			Reason:  reason,
+		})
+	}
+
+	// Identifiers bound to a registry in this file; dynamicIconImports is
+	// distinctive enough to flag even when it arrives some other way
+	registries := map[string]bool{"dynamicIconImports": true}
+
+	for _, m := range namedImportRegex.FindAllStringSubmatchIndex(code, -1) {
+		if m[2] != -1 || !sourceAllowed(code[m[6]:m[7]], sources) {
+			continue
+		}
+		body := code[m[4]:m[5]]
+		for _, sm := range importSpecifierRegex.FindAllStringSubmatchIndex(body, -1) {
+			name := body[sm[4]:sm[5]]
+			if sm[2] != -1 || !registryExports[name] {
+				continue
+			}
+			local := name
+			if sm[6] != -1 {
+				local = body[sm[6]:sm[7]]
+			}
+			registries[local] = true
+			add(m[4]+sm[4], fmt.Sprintf("imports the full icon set (%s)", name))
+		}
+	}
+	for _, m := range namespaceImportRegex.FindAllStringSubmatchIndex(code, -1) {
+		if !sourceAllowed(code[m[4]:m[5]], sources) {
+			continue
+		}
+		registries[code[m[2]:m[3]]] = true
+		add(m[0], fmt.Sprintf("namespace import of %s", code[m[4]:m[5]]))
+	}
+	for _, m := range dynamicModuleImportRegex.FindAllStringSubmatchIndex(code, -1) {
+		if !sourceAllowed(code[m[4]:m[5]], sources) {
+			continue
+		}
+		registries[code[m[2]:m[3]]] = true
+		add(m[0], fmt.Sprintf("imports the full lazy-import map (%s)", code[m[4]:m[5]]))
+	}
+
+	names := make([]string, 0, len(registries))
+	for name := range registries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Also catches casts such as (icons as Record<string, unknown>)[key]
+		lookup := regexp.MustCompile(`(?:^|[^\w$.])(` + regexp.QuoteMeta(name) + `)(?:\s+as\s+[^)\n]*\))?\s*\)?\s*(?:\?\.)?\s*\[`)
+		for _, m := range lookup.FindAllStringSubmatchIndex(code, -1) {
+			add(m[2], fmt.Sprintf("dynamic lookup in %s", name))
+		}
+	}
+	for _, m := range globalRegistryRegex.FindAllStringIndex(code, -1) {
+		add(m[0], "dynamic lookup in the global lucide.icons registry")
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].Line < usages[j].Line
+	})
+	return usages
+}
+
+func createSnippet(lines []string, index int, context int) []string {
+	start := index - context
+	if start < 0 {
+		start = 0
+	}
+	end := index + context
+	if end > len(lines)-1 {
+		end = len(lines) - 1
+	}
+
+	lineNumberWidth := len(strconv.Itoa(end + 1))
+	var snippet []string
+
+	for i := start; i <= end; i++ {
+		prefix := " "
+		if i == index {
+			prefix = ">"
+		}
+		// Format: ">  10 | code"
+		lineNumStr := fmt.Sprintf("%*d", lineNumberWidth, i+1)
+		snippet = append(snippet, fmt.Sprintf("%s %s | %s", prefix, lineNumStr, lines[i]))
+	}
+
+	return snippet
+}
+
+// findImportStatements returns one match per Lucide import statement,
+// reported at its first line with comments and line breaks collapsed.
+// Commented-out imports are ignored.
+func findImportStatements(content, relPath string, sources []string) []LucideMatch {
+	code := blankComments(content)
+
+	var matches []LucideMatch
+	for _, m := range importStatementRegex.FindAllStringSubmatchIndex(code, -1) {
+		if !sourceAllowed(code[m[2]:m[3]], sources) {
+			continue
+		}
+		start := m[0]
+		// Include the rest of the statement, e.g. a closing parenthesis
+		// and semicolon, when it ends on the same line
+		end := m[1]
+		if nl := strings.IndexByte(code[end:], '\n'); nl != -1 {
+			end += nl
+		} else {
+			end = len(code)
+		}
+		matches = append(matches, LucideMatch{
+			File:    relPath,
+			Line:    lineAt(content, start),
+			Content: strings.TrimSpace(whitespaceRegex.ReplaceAllString(code[start:end], " ")),
+		})
+	}
+	return matches
+}
+
+// sourceAllowed reports whether a module specifier belongs to one of the
+// requested packages, either exactly or as a subpath such as lucide/dist/...
+func sourceAllowed(source string, sources []string) bool {
+	if len(sources) == 0 {
+		return true
+	}
+	for _, allowed := range sources {
+		if source == allowed || strings.HasPrefix(source, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIconImports extracts the icons imported from Lucide packages, skipping
+// type-only imports and non-icon exports such as createIcons or LucideProps.
+func parseIconImports(content, relPath string) []IconImport {
+	var imports []IconImport
+
+	for _, m := range namedImportRegex.FindAllStringSubmatchIndex(content, -1) {
+		if m[2] != -1 {
+			continue // import type { ... }
+		}
+		source := content[m[6]:m[7]]
+		// Blank out comments so their words are not read as specifiers
+		body := blankComments(content[m[4]:m[5]])
+		for _, sm := range importSpecifierRegex.FindAllStringSubmatchIndex(body, -1) {
+			if sm[2] != -1 {
+				continue // inline `type X` specifier
+			}
+			icon := body[sm[4]:sm[5]]
+			if !isIconName(icon) {
+				continue
+			}
+			local := icon
+			if sm[6] != -1 {
+				local = body[sm[6]:sm[7]]
+			}
+			imports = append(imports, IconImport{
+				File:   relPath,
+				Line:   lineAt(content, m[4]+sm[4]),
+				Icon:   icon,
+				Local:  local,
+				Source: source,
+			})
+		}
+	}
+
+	for _, m := range iconModuleImportRegex.FindAllStringSubmatchIndex(content, -1) {
+		imports = append(imports, IconImport{
+			File:   relPath,
+			Line:   lineAt(content, m[2]),
+			Icon:   kebabToPascal(content[m[6]:m[7]]),
+			Local:  content[m[2]:m[3]],
+			Source: content[m[4]:m[5]],
+		})
+	}
+
+	sort.SliceStable(imports, func(i, j int) bool {
+		return imports[i].Line < imports[j].Line
+	})
+	return imports
+}
+
+// countIconUses sets Uses on each import to the number of times its local
+// name is referenced in code, ignoring the import statements themselves,
+// comments, and property accesses such as obj.Home.
+func countIconUses(content string, imports []IconImport) {
+	if len(imports) == 0 {
+		return
+	}
+	code := blankComments(content)
+	code = namedImportRegex.ReplaceAllStringFunc(code, blankText)
+	code = iconModuleImportRegex.ReplaceAllStringFunc(code, blankText)
+
+	for i := range imports {
+		offsets := identifierOffsets(code, imports[i].Local)
+		imports[i].Uses = len(offsets)
+		imports[i].UseLines = make([]int, 0, len(offsets))
+		for _, offset := range offsets {
+			imports[i].UseLines = append(imports[i].UseLines, lineAt(code, offset))
+		}
+	}
+}
+
+// identifierOffsets returns the offsets of whole-identifier occurrences of
+// name in code that are not preceded by a dot.
+func identifierOffsets(code, name string) []int {
+	var offsets []int
+	for offset := 0; ; {
+		idx := strings.Index(code[offset:], name)
+		if idx == -1 {
+			return offsets
+		}
+		start := offset + idx
+		end := start + len(name)
+		if (start == 0 || !isIdentifierByte(code[start-1]) && code[start-1] != '.') &&
+			(end == len(code) || !isIdentifierByte(code[end])) {
+			offsets = append(offsets, start)
+		}
+		offset = end
+	}
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isIconName reports whether an imported name looks like a Lucide icon:
+// PascalCase and not one of the known type or helper exports.
+func isIconName(name string) bool {
+	if name == "" || name[0] < 'A' || name[0] > 'Z' {
+		return false
+	}
+	return !nonIconExports[name]
+}
+
+// kebabToPascal converts an icon module name such as chart-column to the
+// exported icon name ChartColumn.
+func kebabToPascal(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
+
+// normalizeIconName maps a user-supplied icon name to its exported form, so
+// data-lucide style names such as chart-column match ChartColumn.
+func normalizeIconName(name string) string {
+	if strings.Contains(name, "-") || name != "" && name[0] >= 'a' && name[0] <= 'z' {
+		return kebabToPascal(name)
+	}
+	return name
+}
+
+// scriptSections blanks everything outside the <script> blocks of a .vue or
+// .svelte component, keeping offsets and line numbers unchanged.
+func scriptSections(content string) string {
+	var b strings.Builder
+	b.Grow(len(content))
+	rest := content
+	for {
+		open := scriptOpenRegex.FindStringIndex(rest)
+		if open == nil {
+			b.WriteString(blankText(rest))
+			return b.String()
+		}
+		b.WriteString(blankText(rest[:open[1]]))
+		rest = rest[open[1]:]
+
+		end := len(rest)
+		if close := scriptCloseRegex.FindStringIndex(rest); close != nil {
+			end = close[0]
+		}
+		b.WriteString(rest[:end])
+		rest = rest[end:]
+	}
+}
+
+// blankComments replaces comments with spaces, keeping byte offsets and line
+// breaks intact so positions in the result map back to content.
+func blankComments(content string) string {
+	return commentRegex.ReplaceAllStringFunc(content, blankText)
+}
+
+// blankText replaces every byte except line breaks with a space.
+func blankText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' {
+			return r
+		}
+		return ' '
+	}, text)
+}
+
+// lineAt returns the 1-based line number of a byte offset in content.
+func lineAt(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+// describeImports lists a file's imported icons, noting any local alias.
+func describeImports(imports []IconImport) []string {
+	names := make([]string, 0, len(imports))
+	for _, imp := range imports {
+		if imp.Local != imp.Icon {
+			names = append(names, fmt.Sprintf("%s (as %s)", imp.Icon, imp.Local))
+		} else {
+			names = append(names, imp.Icon)
+		}
+	}
+	return names
+}
+
+// printByFile prints each file's matched lines and imported icons,
+// files in alphabetical order.
+func printByFile(w io.Writer, grouped map[string][]LucideMatch, importsByFile map[string][]IconImport, heading string, multiToken bool, tokenPattern *regexp.Regexp) {
+	fmt.Fprintln(w, scan.Colorize(heading, scan.AnsiBold))
 
 	// Sort files alphabetically
 	var sortedFiles []string
@@ -107,8 +1158,8 @@ func run() error {
 	sort.Strings(sortedFiles)
 
 	for _, file := range sortedFiles {
-		fmt.Printf("\n%s\n", file)
-		
+		fmt.Fprintf(w, "\n%s\n", scan.Colorize(file, scan.AnsiCyan))
+
 		// Sort entries by line number
 		entries := grouped[file]
 		sort.Slice(entries, func(i, j int) bool {
@@ -116,52 +1167,588 @@ func run() error {
 		})
 
 		for _, entry := range entries {
-			fmt.Printf("  %s (line %d)\n", entry.Content, entry.Line)
+			content := scan.Highlight(entry.Content, tokenPattern, scan.AnsiYellow)
+			if multiToken && entry.Token != "" {
+				fmt.Fprintf(w, "  [%s] %s (line %d)\n", entry.Token, content, entry.Line)
+			} else {
+				fmt.Fprintf(w, "  %s (line %d)\n", content, entry.Line)
+			}
+			for _, line := range entry.Snippet {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+
+		if imports := importsByFile[file]; len(imports) > 0 {
+			fmt.Fprintf(w, "  Icons: %s\n", strings.Join(describeImports(imports), ", "))
 		}
 	}
+}
 
-	fmt.Printf("\nTotal: %d files with Lucide references (%d matches).\n", len(grouped), len(allMatches))
+// printByIcon prints every import and use of each icon, most used first, so
+// all occurrences of one icon can be reviewed together.
+func printByIcon(w io.Writer, imports []IconImport) {
+	byIcon := make(map[string][]IconImport)
+	for _, imp := range imports {
+		byIcon[imp.Icon] = append(byIcon[imp.Icon], imp)
+	}
+	usesByIcon := iconUses(imports)
 
-	return nil
+	icons := make([]string, 0, len(byIcon))
+	for icon := range byIcon {
+		icons = append(icons, icon)
+	}
+	sort.Slice(icons, func(i, j int) bool {
+		if usesByIcon[icons[i]] != usesByIcon[icons[j]] {
+			return usesByIcon[icons[i]] > usesByIcon[icons[j]]
+		}
+		return icons[i] < icons[j]
+	})
+
+	fmt.Fprintln(w, scan.Colorize("Lucide icon usage by icon:", scan.AnsiBold))
+	for _, icon := range icons {
+		entries := byIcon[icon]
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].File != entries[j].File {
+				return entries[i].File < entries[j].File
+			}
+			return entries[i].Line < entries[j].Line
+		})
+
+		fmt.Fprintf(w, "\n%s (%d uses, %d imports)\n", scan.Colorize(icon, scan.AnsiCyan), usesByIcon[icon], len(entries))
+		for _, imp := range entries {
+			label := "import"
+			if imp.Local != imp.Icon {
+				label = "import as " + imp.Local
+			}
+			fmt.Fprintf(w, "  %s:%d (%s)\n", imp.File, imp.Line, label)
+			for _, line := range imp.UseLines {
+				fmt.Fprintf(w, "  %s:%d\n", imp.File, line)
+			}
+		}
+	}
+}
+
+// printIconSet prints the distinct icons imported across all files, each with
+// the number of files importing it.
+func printIconSet(w io.Writer, imports []IconImport) {
+	if len(imports) == 0 {
+		return
+	}
+
+	filesByIcon := make(map[string]map[string]bool)
+	for _, imp := range imports {
+		if filesByIcon[imp.Icon] == nil {
+			filesByIcon[imp.Icon] = make(map[string]bool)
+		}
+		filesByIcon[imp.Icon][imp.File] = true
+	}
+
+	icons := make([]string, 0, len(filesByIcon))
+	maxLen := 0
+	for icon := range filesByIcon {
+		icons = append(icons, icon)
+		if len(icon) > maxLen {
+			maxLen = len(icon)
+		}
+	}
+	sort.Strings(icons)
+
+	fmt.Fprintf(w, "\n%s\n", scan.Colorize(fmt.Sprintf("Imported icons (%d distinct):", len(icons)), scan.AnsiBold))
+	for _, icon := range icons {
+		fileCount := len(filesByIcon[icon])
+		suffix := "s"
+		if fileCount == 1 {
+			suffix = ""
+		}
+		fmt.Fprintf(w, "  %-*s  %d file%s\n", maxLen, icon, fileCount, suffix)
+	}
+}
+
+// printTopIcons ranks icons by how often they are referenced across all
+// files, most used first.
+func printTopIcons(w io.Writer, imports []IconImport) {
+	if len(imports) == 0 {
+		return
+	}
+
+	usesByIcon := iconUses(imports)
+
+	type kv struct {
+		Key   string
+		Value int
+	}
+	var ss []kv
+	for k, v := range usesByIcon {
+		ss = append(ss, kv{k, v})
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		if ss[i].Value != ss[j].Value {
+			return ss[i].Value > ss[j].Value
+		}
+		return ss[i].Key < ss[j].Key
+	})
+
+	limit := 20
+	if len(ss) < limit {
+		limit = len(ss)
+	}
+
+	fmt.Fprintln(w, "\n"+scan.Colorize("Top icons:", scan.AnsiBold))
+	for i := 0; i < limit; i++ {
+		fmt.Fprintf(w, "  %-20s %d\n", ss[i].Key, ss[i].Value)
+	}
+}
+
+// printUnusedImports lists icons that a file imports but never references,
+// which bundlers may still include.
+func printUnusedImports(w io.Writer, imports []IconImport) {
+	var unused []IconImport
+	for _, imp := range imports {
+		if imp.Uses == 0 {
+			unused = append(unused, imp)
+		}
+	}
+	if len(unused) == 0 {
+		return
+	}
+
+	sort.SliceStable(unused, func(i, j int) bool {
+		if unused[i].File != unused[j].File {
+			return unused[i].File < unused[j].File
+		}
+		return unused[i].Line < unused[j].Line
+	})
+
+	fmt.Fprintf(w, "\n%s\n", scan.Colorize(fmt.Sprintf("Imported but unused icons (%d):", len(unused)), scan.AnsiBold))
+	for _, imp := range unused {
+		fmt.Fprintf(w, "  %s:%d  %s\n", imp.File, imp.Line, describeImports([]IconImport{imp})[0])
+	}
+}
+
+// printTokenCounts shows how many matched lines contain each search token.
+func printTokenCounts(w io.Writer, matches []LucideMatch, tokens []string) {
+	counts := make(map[string]int, len(tokens))
+	for _, match := range matches {
+		for _, token := range strings.Split(match.Token, ", ") {
+			counts[token]++
+		}
+	}
+
+	fmt.Fprintln(w, "\nMatches per token:")
+	for _, token := range tokens {
+		fmt.Fprintf(w, "  %-20s %d\n", token, counts[token])
+	}
+}
+
+// iconUses sums the references to each icon across all files.
+func iconUses(imports []IconImport) map[string]int {
+	usesByIcon := make(map[string]int)
+	for _, imp := range imports {
+		usesByIcon[imp.Icon] += imp.Uses
+	}
+	return usesByIcon
+}
+
+// printJSON writes the matches grouped by file, the parsed imports, the
+// per-icon usage counts, and the severity of each finding kind as an indented
+// JSON document.
+func printJSON(w io.Writer, matches []LucideMatch, imports []IconImport, analysis iconAnalysis, withTokens bool, policy scan.SeverityPolicy) error {
+	report := jsonReport{
+		Files:        make(map[string][]jsonMatch),
+		TotalMatches: len(matches),
+		IconUses:     iconUses(imports),
+		Imports:      make([]jsonImport, 0, len(imports)),
+		Severity:     policy.Levels(),
+	}
+
+	sorted := append([]LucideMatch(nil), matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+	for _, match := range sorted {
+		entry := jsonMatch{Line: match.Line, Content: match.Content, Snippet: match.Snippet}
+		if withTokens {
+			entry.Token = match.Token
+		}
+		report.Files[match.File] = append(report.Files[match.File], entry)
+	}
+	report.TotalFiles = len(report.Files)
+
+	report.Imports = toJSONImports(imports)
+	if len(analysis.unknown) > 0 {
+		report.UnknownIcons = toJSONImports(analysis.unknown)
+	}
+	report.Size = analysis.size
+	report.DeprecatedIcons = analysis.deprecated
+	report.DynamicUsage = analysis.dynamic
+	if report.DynamicUsage == nil {
+		report.DynamicUsage = []DynamicUsage{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
 }
 
-// scanFile reads a file and finds lines containing the match token.
-func scanFile(absPath, projectRoot string) ([]LucideMatch, error) {
-	file, err := os.Open(absPath)
+// gitChangedFiles returns the files changed between base and HEAD, or with
+// staged the files staged for commit, as slash paths relative to projectRoot
+func gitChangedFiles(projectRoot, base string, staged bool) (map[string]bool, error) {
+	revision := base + "...HEAD"
+	if staged {
+		revision = "--cached"
+	}
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", revision, "--")
+	cmd.Dir = projectRoot
+	out, err := cmd.Output()
 	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s: %s", revision, strings.TrimSpace(string(exitErr.Stderr)))
+		}
 		return nil, err
 	}
-	defer file.Close()
 
-	var matches []LucideMatch
-	scanner := bufio.NewScanner(file)
-	lineNumber := 0
+	files := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files[filepath.ToSlash(line)] = true
+		}
+	}
+	return files, nil
+}
 
-	// Replicates path.relative logic
-	relPath, err := filepath.Rel(projectRoot, absPath)
+// toJSONImports converts imports to their JSON form, ordered by file and line.
+func toJSONImports(imports []IconImport) []jsonImport {
+	result := make([]jsonImport, 0, len(imports))
+	for _, imp := range sortImports(imports) {
+		result = append(result, jsonImport{
+			File:     imp.File,
+			Line:     imp.Line,
+			Icon:     imp.Icon,
+			Local:    imp.Local,
+			Source:   imp.Source,
+			Uses:     imp.Uses,
+			UseLines: imp.UseLines,
+		})
+	}
+	return result
+}
+
+// loadAllowedIcons reads the approved icon names for --allowed. Names may be
+// written as exported (ChartColumn) or as in data-lucide (chart-column).
+func loadAllowedIcons(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		relPath = absPath // Fallback if rel path fails
+		return nil, err
 	}
-	// Replicates .replace(/\\/g, '/') for consistent output style
-	relPath = filepath.ToSlash(relPath)
 
-	for scanner.Scan() {
-		lineNumber++
-		lineContent := scanner.Text()
-		
-		// Case-insensitive check
-		if strings.Contains(strings.ToLower(lineContent), matchToken) {
-			matches = append(matches, LucideMatch{
-				File:    relPath,
-				Line:    lineNumber,
-				Content: strings.TrimSpace(lineContent),
-			})
+	allowed := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+		name := strings.TrimSpace(line)
+		if name == "" {
+			continue
+		}
+		allowed[normalizeIconName(name)] = true
+	}
+	return allowed, nil
+}
+
+// findUnknownIcons returns the imports whose icon is not in the allowed set.
+func findUnknownIcons(imports []IconImport, allowed map[string]bool) []IconImport {
+	var unknown []IconImport
+	for _, imp := range imports {
+		if !allowed[imp.Icon] {
+			unknown = append(unknown, imp)
+		}
+	}
+	sort.SliceStable(unknown, func(i, j int) bool {
+		if unknown[i].Icon != unknown[j].Icon {
+			return unknown[i].Icon < unknown[j].Icon
+		}
+		if unknown[i].File != unknown[j].File {
+			return unknown[i].File < unknown[j].File
 		}
+		return unknown[i].Line < unknown[j].Line
+	})
+	return unknown
+}
+
+// printUnknownIcons lists each icon outside the approved set with the places
+// that import it.
+func printUnknownIcons(w io.Writer, unknown []IconImport) {
+	if len(unknown) == 0 {
+		fmt.Fprintln(w, "\nAll imported icons are in the approved set.")
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s\n", scan.Colorize(fmt.Sprintf("Icons not in the approved set (%d imports):", len(unknown)), scan.AnsiBold))
+	for i, imp := range unknown {
+		if i == 0 || unknown[i-1].Icon != imp.Icon {
+			fmt.Fprintf(w, "  %s\n", scan.Colorize(imp.Icon, scan.AnsiRed))
+		}
+		fmt.Fprintf(w, "    %s:%d\n", imp.File, imp.Line)
 	}
+}
 
-	if err := scanner.Err(); err != nil {
+// loadIconSizes reads a JSON object mapping icon names (PascalCase or
+// kebab-case) to their approximate size in bytes.
+func loadIconSizes(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
 		return nil, err
 	}
+	var raw map[string]int
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	sizes := make(map[string]int, len(raw))
+	for name, bytes := range raw {
+		sizes[normalizeIconName(name)] = bytes
+	}
+	return sizes, nil
+}
 
-	return matches, nil
-}
\ No newline at end of file
+// estimateIconPayload sums the size of each distinct imported icon, taking
+// it from sizes when listed and defaultBytes otherwise.
+func estimateIconPayload(imports []IconImport, defaultBytes int, sizes map[string]int) *sizeEstimate {
+	estimate := &sizeEstimate{IconBytes: make(map[string]int)}
+	for _, imp := range imports {
+		if _, seen := estimate.IconBytes[imp.Icon]; seen {
+			continue
+		}
+		bytes, ok := sizes[imp.Icon]
+		if !ok {
+			bytes = defaultBytes
+		}
+		estimate.IconBytes[imp.Icon] = bytes
+		estimate.TotalBytes += bytes
+	}
+	estimate.DistinctIcons = len(estimate.IconBytes)
+	return estimate
+}
+
+// printSizeEstimate prints the estimated icon payload and, when sizes
+// differ between icons, the largest contributors.
+func printSizeEstimate(w io.Writer, estimate sizeEstimate) {
+	fmt.Fprintf(w, "\nEstimated icon payload: %s across %d distinct icons\n", formatBytes(estimate.TotalBytes), estimate.DistinctIcons)
+
+	type kv struct {
+		Key   string
+		Value int
+	}
+	var ss []kv
+	uniform := true
+	for k, v := range estimate.IconBytes {
+		ss = append(ss, kv{k, v})
+		if len(ss) > 1 && v != ss[0].Value {
+			uniform = false
+		}
+	}
+	if uniform {
+		return
+	}
+	sort.Slice(ss, func(i, j int) bool {
+		if ss[i].Value != ss[j].Value {
+			return ss[i].Value > ss[j].Value
+		}
+		return ss[i].Key < ss[j].Key
+	})
+
+	limit := 10
+	if len(ss) < limit {
+		limit = len(ss)
+	}
+	fmt.Fprintln(w, "Largest icons:")
+	for i := 0; i < limit; i++ {
+		fmt.Fprintf(w, "  %-20s %s\n", ss[i].Key, formatBytes(ss[i].Value))
+	}
+}
+
+// formatBytes renders a byte count as B or KiB with one decimal place.
+func formatBytes(bytes int) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	return fmt.Sprintf("%.1f KiB", float64(bytes)/1024)
+}
+
+// printDynamicUsage warns about runtime icon lookups and whole-set imports,
+// which static import analysis cannot account for.
+func printDynamicUsage(w io.Writer, usages []DynamicUsage) {
+	if len(usages) == 0 {
+		return
+	}
+
+	sorted := append([]DynamicUsage(nil), usages...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+
+	fmt.Fprintf(w, "\n%s\n", scan.Colorize(fmt.Sprintf("⚠️  Dynamic icon usage (%d) - may bundle the entire icon set:", len(sorted)), scan.AnsiBold))
+	for _, usage := range sorted {
+		fmt.Fprintf(w, "  %s:%d  %s\n", usage.File, usage.Line, scan.Colorize(usage.Reason, scan.AnsiYellow))
+		fmt.Fprintf(w, "    %s\n", usage.Content)
+	}
+}
+
+// sortImports orders imports by file, then line.
+func sortImports(imports []IconImport) []IconImport {
+	sorted := append([]IconImport(nil), imports...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].File != sorted[j].File {
+			return sorted[i].File < sorted[j].File
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+	return sorted
+}
+
+// printCSV writes the icon inventory as RFC 4180 CSV: one row for each
+// import and each use of an icon.
+func printCSV(w io.Writer, imports []IconImport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"icon", "file", "line", "kind"}); err != nil {
+		return err
+	}
+	for _, imp := range sortImports(imports) {
+		if err := writer.Write([]string{imp.Icon, imp.File, strconv.Itoa(imp.Line), "import"}); err != nil {
+			return err
+		}
+		for _, line := range imp.UseLines {
+			if err := writer.Write([]string{imp.Icon, imp.File, strconv.Itoa(line), "use"}); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// printMarkdown renders the icon inventory and the top icons as
+// GitHub-flavored Markdown tables.
+func printMarkdown(w io.Writer, imports []IconImport) {
+	fmt.Fprintln(w, "## Lucide icon inventory")
+	fmt.Fprintln(w)
+	if len(imports) == 0 {
+		fmt.Fprintln(w, "No Lucide icon imports found.")
+		return
+	}
+
+	fmt.Fprintln(w, "| Icon | Imported as | File | Line | Uses |")
+	fmt.Fprintln(w, "| --- | --- | --- | ---: | ---: |")
+	for _, imp := range sortImports(imports) {
+		fmt.Fprintf(w, "| %s | %s | `%s` | %d | %d |\n", imp.Icon, imp.Local, escapeMarkdownCell(imp.File), imp.Line, imp.Uses)
+	}
+
+	usesByIcon := iconUses(imports)
+	icons := make([]string, 0, len(usesByIcon))
+	for icon := range usesByIcon {
+		icons = append(icons, icon)
+	}
+	sort.Slice(icons, func(i, j int) bool {
+		if usesByIcon[icons[i]] != usesByIcon[icons[j]] {
+			return usesByIcon[icons[i]] > usesByIcon[icons[j]]
+		}
+		return icons[i] < icons[j]
+	})
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Top icons")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Icon | Uses |")
+	fmt.Fprintln(w, "| --- | ---: |")
+	for _, icon := range icons {
+		fmt.Fprintf(w, "| %s | %d |\n", icon, usesByIcon[icon])
+	}
+}
+
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+// loadDeprecatedIcons reads a JSON object mapping old icon names to their
+// replacements; either side may be PascalCase or kebab-case.
+func loadDeprecatedIcons(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	renames := make(map[string]string, len(raw))
+	for oldName, newName := range raw {
+		renames[normalizeIconName(oldName)] = normalizeIconName(newName)
+	}
+	return renames, nil
+}
+
+// findDeprecatedIcons returns the imports of icons that have been renamed,
+// ordered by file and line.
+func findDeprecatedIcons(imports []IconImport, renames map[string]string) []DeprecatedIcon {
+	var deprecated []DeprecatedIcon
+	for _, imp := range sortImports(imports) {
+		if replacement, ok := renames[imp.Icon]; ok {
+			deprecated = append(deprecated, DeprecatedIcon{
+				File:        imp.File,
+				Line:        imp.Line,
+				Icon:        imp.Icon,
+				Replacement: replacement,
+			})
+		}
+	}
+	return deprecated
+}
+
+// printDeprecatedIcons lists renamed icons with the name to switch to.
+func printDeprecatedIcons(w io.Writer, deprecated []DeprecatedIcon) {
+	if len(deprecated) == 0 {
+		fmt.Fprintln(w, "\nNo deprecated icon names in use.")
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s\n", scan.Colorize(fmt.Sprintf("Deprecated icon names (%d):", len(deprecated)), scan.AnsiBold))
+	for _, d := range deprecated {
+		fmt.Fprintf(w, "  %s:%d  %s -> %s\n", d.File, d.Line, scan.Colorize(d.Icon, scan.AnsiRed), d.Replacement)
+	}
+}
+
+// checkMaxIcons reports whether the distinct imported icons fit the
+// --max-icons budget. When they do not, it names the least-used icons beyond
+// the limit, the cheapest ones to drop.
+func checkMaxIcons(w io.Writer, imports []IconImport, maxIcons int) bool {
+	usesByIcon := iconUses(imports)
+	if len(usesByIcon) <= maxIcons {
+		return true
+	}
+
+	icons := make([]string, 0, len(usesByIcon))
+	for icon := range usesByIcon {
+		icons = append(icons, icon)
+	}
+	sort.Slice(icons, func(i, j int) bool {
+		if usesByIcon[icons[i]] != usesByIcon[icons[j]] {
+			return usesByIcon[icons[i]] > usesByIcon[icons[j]]
+		}
+		return icons[i] < icons[j]
+	})
+
+	fmt.Fprintf(w, "\n❌ %d distinct icons imported, over the --max-icons limit of %d.\n", len(icons), maxIcons)
+	fmt.Fprintln(w, "Least-used icons beyond the limit:")
+	for _, icon := range icons[maxIcons:] {
+		fmt.Fprintf(w, "  %-20s %d\n", icon, usesByIcon[icon])
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+//go:build ignore
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Run with: go test scripts/find-lucide-usage.go scripts/find-lucide-usage_test.go
+
+// describe flattens an import to "line:Icon>Local@Source" for comparison.
+func describe(imports []IconImport) []string {
+	var out []string
+	for _, imp := range imports {
+		out = append(out, fmt.Sprintf("%d:%s>%s@%s", imp.Line, imp.Icon, imp.Local, imp.Source))
+	}
+	return out
+}
+
+func TestParseIconImports(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "single-line named import",
+			content: "import { Home, Settings } from 'lucide-react';\n",
+			want:    []string{"1:Home>Home@lucide-react", "1:Settings>Settings@lucide-react"},
+		},
+		{
+			name:    "multi-line named import",
+			content: "import {\n  Home,\n  Settings,\n} from \"lucide-react\";\n",
+			want:    []string{"2:Home>Home@lucide-react", "3:Settings>Settings@lucide-react"},
+		},
+		{
+			name:    "alias",
+			content: "import { ChartColumn as BarChart3 } from 'lucide-vue-next';\n",
+			want:    []string{"1:ChartColumn>BarChart3@lucide-vue-next"},
+		},
+		{
+			name:    "default and named import",
+			content: "import lucide, { Home } from '@lucide/svelte';\n",
+			want:    []string{"1:Home>Home@@lucide/svelte"},
+		},
+		{
+			name:    "type-only import is skipped",
+			content: "import type { LucideIcon, Home } from 'lucide-react';\n",
+			want:    nil,
+		},
+		{
+			name:    "inline type specifier and non-icon exports are skipped",
+			content: "import { type Home, createIcons, LucideProps, Icon, Trash2 } from 'lucide';\n",
+			want:    []string{"1:Trash2>Trash2@lucide"},
+		},
+		{
+			name:    "comment inside the specifier list",
+			content: "import {\n  // Menu is unused\n  Home, /* Old */\n} from 'lucide-react';\n",
+			want:    []string{"3:Home>Home@lucide-react"},
+		},
+		{
+			name:    "lucide-react/icons subpath",
+			content: "import House from 'lucide-react/icons/house';\nimport Chart from 'lucide-react/icons/chart-column.js';\n",
+			want:    []string{"1:House>House@lucide-react/icons/house", "2:ChartColumn>Chart@lucide-react/icons/chart-column.js"},
+		},
+		{
+			name:    "dist icon module",
+			content: "import BarChart3 from 'lucide/dist/esm/icons/chart-column.js';\n",
+			want:    []string{"1:ChartColumn>BarChart3@lucide/dist/esm/icons/chart-column.js"},
+		},
+		{
+			name:    "namespace import binds no individual icons",
+			content: "import * as Icons from 'lucide-react';\n",
+			want:    nil,
+		},
+		{
+			name:    "other packages are ignored",
+			content: "import { Home } from 'react-icons';\nimport { Home as H } from '@acme/lucide';\n",
+			want:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := describe(parseIconImports(tc.content, "a.tsx"))
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCountIconUses(t *testing.T) {
+	content := strings.Join([]string{
+		"import { Home, Settings as Gear, Trash2 } from 'lucide-react';",
+		"import Chart from 'lucide-react/icons/chart-column';",
+		"// <Trash2 /> is not rendered yet",
+		"const a = <Home />;",
+		"const b = [Home, Gear];",
+		"const c = menu.Trash2 + HomeButton;",
+		"/* Chart */",
+	}, "\n")
+	imports := parseIconImports(content, "a.tsx")
+	countIconUses(content, imports)
+
+	want := map[string][]int{
+		"Home":        {4, 5},
+		"Settings":    {5},
+		"Trash2":      {},
+		"ChartColumn": {},
+	}
+	if len(imports) != len(want) {
+		t.Fatalf("got %d imports, want %d: %q", len(imports), len(want), describe(imports))
+	}
+	for _, imp := range imports {
+		wantLines, ok := want[imp.Icon]
+		if !ok {
+			t.Errorf("unexpected import %s", imp.Icon)
+			continue
+		}
+		if imp.Uses != len(wantLines) || !reflect.DeepEqual(imp.UseLines, wantLines) {
+			t.Errorf("%s: got %d uses on lines %v, want %d on %v", imp.Icon, imp.Uses, imp.UseLines, len(wantLines), wantLines)
+		}
+	}
+
+	if got := iconUses(imports); got["Home"] != 2 || got["Settings"] != 1 || got["Trash2"] != 0 {
+		t.Errorf("iconUses = %v", got)
+	}
+}
+
+func TestPrintUnusedImports(t *testing.T) {
+	cases := []struct {
+		name    string
+		imports []IconImport
+		want    string
+	}{
+		{
+			name:    "all used",
+			imports: []IconImport{{File: "a.tsx", Line: 1, Icon: "Home", Local: "Home", Uses: 2}},
+			want:    "",
+		},
+		{
+			name: "unused sorted by file and line, aliases noted",
+			imports: []IconImport{
+				{File: "b.tsx", Line: 3, Icon: "Home", Local: "Home"},
+				{File: "a.tsx", Line: 7, Icon: "Trash2", Local: "Trash2"},
+				{File: "a.tsx", Line: 2, Icon: "Settings", Local: "Gear"},
+				{File: "a.tsx", Line: 1, Icon: "Menu", Local: "Menu", Uses: 1},
+			},
+			want: "\nImported but unused icons (3):\n" +
+				"  a.tsx:2  Settings (as Gear)\n" +
+				"  a.tsx:7  Trash2\n" +
+				"  b.tsx:3  Home\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			printUnusedImports(&b, tc.imports)
+			if got := b.String(); got != tc.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFindDynamicUsage(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		sources []string
+		want    []string
+	}{
+		{
+			name:    "static icon imports",
+			content: "import { Home } from 'lucide-react';\nconst a = <Home />;\n",
+			want:    nil,
+		},
+		{
+			name:    "icons registry import and lookup",
+			content: "import { icons } from 'lucide-react';\nconst Icon = icons[name];\n",
+			want: []string{
+				"1:imports the full icon set (icons)",
+				"2:dynamic lookup in icons",
+			},
+		},
+		{
+			name:    "aliased registry with a cast and optional chaining",
+			content: "import { icons as all } from 'lucide-react';\nconst Icon = (all as Record<string, unknown>)?.[name];\n",
+			want: []string{
+				"1:imports the full icon set (icons)",
+				"2:dynamic lookup in all",
+			},
+		},
+		{
+			name:    "namespace import and lookup",
+			content: "import * as Icons from 'lucide-react';\nconst Icon = Icons[name];\nconst Home = Icons.Home;\n",
+			want: []string{
+				"1:namespace import of lucide-react",
+				"2:dynamic lookup in Icons",
+			},
+		},
+		{
+			name:    "lazy-import map",
+			content: "import dynamicIconImports from 'lucide-react/dynamicIconImports';\nconst load = dynamicIconImports[name]();\n",
+			want: []string{
+				"1:imports the full lazy-import map (lucide-react/dynamicIconImports)",
+				"2:dynamic lookup in dynamicIconImports",
+			},
+		},
+		{
+			name:    "global registry",
+			content: "const svg = window.lucide?.icons?.[name];\n",
+			want:    []string{"1:dynamic lookup in the global lucide.icons registry"},
+		},
+		{
+			name:    "commented-out lookup",
+			content: "import { Home } from 'lucide-react';\n// const Icon = icons[name];\n/* lucide.icons[name] */\n",
+			want:    nil,
+		},
+		{
+			name:    "type-only registry import",
+			content: "import type { icons } from 'lucide-react';\n",
+			want:    nil,
+		},
+		{
+			name:    "source outside --source",
+			content: "import * as Icons from 'lucide-vue-next';\n",
+			sources: []string{"lucide-react"},
+			want:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, usage := range findDynamicUsage(tc.content, "a.tsx", tc.sources) {
+				got = append(got, fmt.Sprintf("%d:%s", usage.Line, usage.Reason))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
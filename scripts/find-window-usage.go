@@ -1,26 +1,51 @@
+//go:build ignore
+
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
 )
 
 // -- Structs and Types --
 
 type WindowUsageMatch struct {
 	Line        int
+	Content     string
 	Snippet     []string
 	Identifiers []string
 }
 
+// jsonFinding is one finding in the --format=json report, in the schema
+// shared by every scanner (see scripts/ff-scan.go)
+type jsonFinding struct {
+	Tool     string `json:"tool"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Kind     string `json:"kind"`
+}
+
+// jsonReport is the document written by --format=json
+type jsonReport struct {
+	Tool     string        `json:"tool"`
+	Findings []jsonFinding `json:"findings"`
+}
+
 // stringSlice handles comma-separated flags or multiple flag occurrences
 type stringSlice []string
 
@@ -40,23 +65,48 @@ func (s *stringSlice) Set(value string) error {
 
 // -- Constants and Globals --
 
+// Output formats accepted by --format
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// logOut receives informational lines; stderr when stdout carries JSON
+var logOut io.Writer = os.Stdout
+
+// Exit codes shared by every scanner
+const (
+	exitFindings = 1 // findings over a --fail-on-* / --max threshold
+	exitError    = 2 // invalid usage or a runtime/I/O error
+)
+
+// exitCode is set by run when a --fail-on-match, --max, or --fail-on check fails
+var exitCode int
+
+// kindWindowAccess is the kind of every finding, for --severity
+const kindWindowAccess = "window-access"
+
+// run fills in stats for the --stats footer, which --quiet suppresses along
+// with the timing line
+var (
+	showStats bool
+	quiet     bool
+	stats     scan.Stats
+)
+
 var defaultExtensions = map[string]bool{
 	".ts": true, ".tsx": true, ".js": true, ".jsx": true,
 	".cts": true, ".mts": true, ".cjs": true, ".mjs": true,
 }
 
-var excludedDirs = map[string]bool{
-	"node_modules": true, "dist": true, "out": true, "build": true,
-	"coverage": true, ".git": true, ".idea": true, ".vscode": true,
-	"lib": true, "debug_logs": true,
-}
-
 // Regex patterns compiled at initialization
 var (
 	windowPropertyRegex        = regexp.MustCompile(`\bwindow\s*(?:\?\.|\.)[A-Za-z_$]`)
 	windowBracketRegex         = regexp.MustCompile(`\bwindow\s*\[`)
 	windowPropertyCaptureRegex = regexp.MustCompile(`\bwindow\s*(?:\?\.|\.)\s*([A-Za-z_$][\w$]*)`)
 	windowBracketCaptureRegex  = regexp.MustCompile(`\bwindow\s*\[\s*['"]([^'"]+)['"]\s*\]`)
+	// windowAccessRegex spans the window access highlighted with --color
+	windowAccessRegex = regexp.MustCompile(`\bwindow\s*(?:(?:\?\.|\.)\s*[A-Za-z_$][\w$]*|\[)`)
 )
 
 // -- Helper Functions --
@@ -145,6 +195,7 @@ func collectWindowUsage(content string, context int, pattern *regexp.Regexp) []W
 
 		matches = append(matches, WindowUsageMatch{
 			Line:        i + 1,
+			Content:     strings.TrimSpace(line),
 			Snippet:     createSnippet(lines, i, context),
 			Identifiers: extractIdentifiers(line),
 		})
@@ -152,14 +203,87 @@ func collectWindowUsage(content string, context int, pattern *regexp.Regexp) []W
 	return matches
 }
 
+// scanFiles collects the window usage of each file on workerCount goroutines.
+// Results are indexed like paths. The second result is the number of lines
+// read; the error is the first file that could not be read.
+func scanFiles(paths []string, context int, pattern *regexp.Regexp, workerCount int) ([][]WindowUsageMatch, int, error) {
+	type fileUsage struct {
+		matches []WindowUsageMatch
+		lines   int
+	}
+	usages, err := scan.Parallel(paths, workerCount, func(path string) (fileUsage, error) {
+		contentBytes, err := os.ReadFile(path)
+		if err != nil {
+			return fileUsage{}, err
+		}
+		content := string(contentBytes)
+		return fileUsage{
+			matches: collectWindowUsage(content, context, pattern),
+			// Count lines the way a line scanner would, ignoring the final newline
+			lines: strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1,
+		}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	results := make([][]WindowUsageMatch, len(usages))
+	totalLines := 0
+	for index, usage := range usages {
+		results[index] = usage.matches
+		totalLines += usage.lines
+	}
+	return results, totalLines, nil
+}
+
 // -- Main Logic --
 
+// gitChangedFiles returns the files changed between base and HEAD, or with
+// staged the files staged for commit, as slash paths relative to projectRoot
+func gitChangedFiles(projectRoot, base string, staged bool) (map[string]bool, error) {
+	revision := base + "...HEAD"
+	if staged {
+		revision = "--cached"
+	}
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", revision, "--")
+	cmd.Dir = projectRoot
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s: %s", revision, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			changed[filepath.ToSlash(line)] = true
+		}
+	}
+	return changed, nil
+}
+
 func run() {
 	// Flags
 	var context int
 	var rootFlags stringSlice
 	var extensionFlags stringSlice
+	var excludeFlags stringSlice
+	var useGitignore bool
 	var patternStr string
+	var format string
+	var workers int
+	var failOnMatch bool
+	var maxMatches int
+	var changed bool
+	var changedBase string
+	var staged bool
+	var colorMode string
+	var baselinePath string
+	var updateBaseline bool
+	var severityFlag string
+	var failOn string
 
 	flag.IntVar(&context, "context", 2, "Number of context lines")
 	// Support both --root and --roots logic by binding same var or checking args
@@ -168,13 +292,57 @@ func run() {
 	flag.Var(&rootFlags, "roots", "Root directories (alias)")
 	flag.StringVar(&patternStr, "pattern", "", "Regex pattern to filter lines")
 	flag.Var(&extensionFlags, "extensions", "File extensions to scan")
+	flag.Var(&excludeFlags, "exclude", "Skip files matching these globs, e.g. **/*.spec.ts; a bare name such as fixtures skips directories with that name, in addition to node_modules, dist, and the other defaults (repeatable or comma-separated)")
+	flag.BoolVar(&useGitignore, "use-gitignore", false, "Skip files ignored by git (.gitignore, .git/info/exclude, global excludes)")
+	flag.StringVar(&format, "format", formatText, "Output format: text or json")
+	flag.BoolVar(&failOnMatch, "fail-on-match", false, "Exit with code 1 when any window usage is found (same as --max=0)")
+	flag.IntVar(&maxMatches, "max", -1, "Exit with code 1 when more than N window usages are found (-1 disables the check)")
+	flag.StringVar(&severityFlag, "severity", "", "Severity per finding kind as kind=error|warning|info, e.g. window-access=warning (default info)")
+	flag.StringVar(&failOn, "fail-on", "", "Exit with code 1 when more than N window usages (default 0) are at LEVEL severity or above, as LEVEL[:N]")
+	flag.IntVar(&workers, "workers", runtime.NumCPU(), "Number of worker goroutines scanning files in parallel")
+	flag.BoolVar(&changed, "changed", false, "Only scan files changed on this branch (git diff --name-only <base>...HEAD)")
+	flag.StringVar(&changedBase, "changed-base", "origin/main", "Base ref used by --changed")
+	flag.BoolVar(&staged, "staged", false, "Only scan files staged for commit (git diff --cached --name-only), e.g. from a pre-commit hook")
+	flag.StringVar(&baselinePath, "baseline", "", "Baseline JSON of known window usages keyed by file and line text (e.g. window-baseline.json); only new ones are reported")
+	flag.BoolVar(&updateBaseline, "update-baseline", false, "Rewrite the --baseline file with the current window usages and exit")
+	flag.BoolVar(&showStats, "stats", false, "Print a footer with files scanned, lines inspected, matches, and elapsed time")
+	flag.BoolVar(&quiet, "quiet", false, "Suppress the timing line and the --stats footer")
+	flag.StringVar(&colorMode, "color", scan.ColorAuto, "Color the text report: auto (terminal without NO_COLOR), always, or never")
 	flag.Parse()
 
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if failOnMatch && maxMatches < 0 {
+		maxMatches = 0
+	}
+	policy, err := scan.NewSeverityPolicy(severityFlag, failOn, map[string]string{kindWindowAccess: scan.SeverityInfo})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
+	switch format {
+	case formatText:
+	case formatJSON:
+		logOut = os.Stderr
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid --format value \"%s\" (expected text or json)\n", format)
+		os.Exit(exitError)
+	}
+
+	enabled, err := scan.ColorEnabled(colorMode, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	scan.SetColor(enabled && format == formatText)
+
 	// 1. Setup Configuration
 	projectRoot, err := os.Getwd()
 	if err != nil {
 		fmt.Printf("Error getting current directory: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
 	// Resolve Roots
@@ -204,7 +372,7 @@ func run() {
 	if patternStr != "" {
 		p, err := regexp.Compile("(?i)" + patternStr) // case insensitive
 		if err != nil {
-			fmt.Printf("Invalid pattern \"%s\": %v\n", patternStr, err)
+			fmt.Fprintf(logOut, "Invalid pattern \"%s\": %v\n", patternStr, err)
 			// Proceeding without pattern as per original script logic (it warns)
 		} else {
 			pattern = p
@@ -212,50 +380,61 @@ func run() {
 	}
 
 	// 2. Gather Files
-	filesSet := make(map[string]struct{})
-
+	var absRoots []string
 	for _, r := range roots {
 		absRoot := filepath.Join(projectRoot, r)
-		info, err := os.Stat(absRoot)
-		if err != nil {
-			fmt.Printf("Skipping missing path: %s\n", r)
+		if _, err := os.Stat(absRoot); err != nil {
+			fmt.Fprintf(logOut, "Skipping missing path: %s\n", r)
 			continue
 		}
+		absRoots = append(absRoots, absRoot)
+	}
 
-		if !info.IsDir() {
-			// Single file check
-			ext := strings.ToLower(filepath.Ext(absRoot))
-			if extensions[ext] {
-				filesSet[absRoot] = struct{}{}
-			}
-			continue
-		}
+	// A root that is a single file is scanned when its extension matches
+	found, err := scan.WalkSourceFiles(absRoots, extensions, scan.NewExcludes(excludeFlags), useGitignore)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error walking directories: %v\n", err)
+		os.Exit(exitError)
+	}
+	filesSet := make(map[string]struct{}, len(found))
+	for _, path := range found {
+		filesSet[path] = struct{}{}
+	}
 
-		// Directory Walk
-		err = filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if d.IsDir() {
-				if excludedDirs[d.Name()] {
-					return filepath.SkipDir
-				}
-				return nil
+	if changed || staged {
+		changedFiles, err := gitChangedFiles(projectRoot, changedBase, staged)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing changed files: %v\n", err)
+			os.Exit(exitError)
+		}
+		for path := range filesSet {
+			relPath, _ := filepath.Rel(projectRoot, path)
+			if !changedFiles[filepath.ToSlash(relPath)] {
+				delete(filesSet, path)
 			}
+		}
+	}
 
-			ext := strings.ToLower(filepath.Ext(d.Name()))
-			if extensions[ext] {
-				filesSet[path] = struct{}{}
-			}
-			return nil
-		})
-		if err != nil {
-			fmt.Printf("Error walking directory %s: %v\n", r, err)
+	if updateBaseline && baselinePath == "" {
+		fmt.Fprintln(os.Stderr, "--update-baseline requires --baseline=FILE")
+		os.Exit(exitError)
+	}
+	var baseline scan.Baseline
+	baselineFile := filepath.Join(projectRoot, baselinePath)
+	if baselinePath != "" {
+		if updateBaseline {
+			baseline = scan.NewBaseline()
+		} else if baseline, err = scan.LoadBaseline(baselineFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading baseline: %v\n", err)
+			os.Exit(exitError)
 		}
 	}
 
-	if len(filesSet) == 0 {
-		fmt.Println("No files found to scan.")
+	if len(filesSet) == 0 && !updateBaseline {
+		fmt.Fprintln(logOut, "No files found to scan.")
+		if format == formatJSON {
+			printJSON(nil, policy)
+		}
 		return
 	}
 
@@ -270,19 +449,38 @@ func run() {
 		allFiles = append(allFiles, f)
 	}
 
-	for _, filePath := range allFiles {
-		contentBytes, err := os.ReadFile(filePath)
-		if err != nil {
-			continue
-		}
+	fileMatches, lines, err := scanFiles(allFiles, context, pattern, workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning files: %v\n", err)
+		os.Exit(exitError)
+	}
+	matchCount := 0
+	// baselined counts the usages suppressed by, or with --update-baseline
+	// recorded in, the baseline
+	baselined := 0
+	for i, filePath := range allFiles {
+		matches := fileMatches[i]
+		relPath, _ := filepath.Rel(projectRoot, filePath)
+		relPath = filepath.ToSlash(relPath) // Force forward slashes for consistency
 
-		matches := collectWindowUsage(string(contentBytes), context, pattern)
+		if baseline != nil {
+			kept := matches[:0]
+			for _, match := range matches {
+				if updateBaseline {
+					baseline.Add(relPath, match.Content)
+					baselined++
+				} else if baseline.Take(relPath, match.Content) {
+					baselined++
+				} else {
+					kept = append(kept, match)
+				}
+			}
+			matches = kept
+		}
 		if len(matches) == 0 {
 			continue
 		}
-
-		relPath, _ := filepath.Rel(projectRoot, filePath)
-		relPath = filepath.ToSlash(relPath) // Force forward slashes for consistency
+		matchCount += len(matches)
 		matchesByFile[relPath] = matches
 
 		for _, match := range matches {
@@ -292,6 +490,43 @@ func run() {
 		}
 	}
 
+	stats = scan.Stats{Files: len(allFiles), Lines: lines, Matches: matchCount}
+
+	if updateBaseline {
+		if err := scan.WriteBaseline(baselineFile, baseline); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+			os.Exit(exitError)
+		}
+		fmt.Fprintf(logOut, "📝 Baseline %s updated with %d window usages.\n", baselinePath, baselined)
+		return
+	}
+	if baselined > 0 {
+		defer fmt.Fprintf(logOut, "Baseline: %d known window usages suppressed\n", baselined)
+	}
+
+	// Checked after the report so the failure message follows it
+	if maxMatches >= 0 && matchCount > maxMatches {
+		defer func() {
+			fmt.Fprintf(os.Stderr, "❌ %d window usages exceeds the allowed maximum of %d\n", matchCount, maxMatches)
+			exitCode = exitFindings
+		}()
+	}
+	matchKinds := make([]string, matchCount)
+	for i := range matchKinds {
+		matchKinds[i] = kindWindowAccess
+	}
+	if count, failed := policy.Failing(matchKinds); failed {
+		defer func() {
+			fmt.Fprintf(os.Stderr, "❌ %d window usages at %s severity or above exceeds the --fail-on maximum of %d\n", count, policy.FailOn, policy.FailMax)
+			exitCode = exitFindings
+		}()
+	}
+
+	if format == formatJSON {
+		printJSON(matchesByFile, policy)
+		return
+	}
+
 	if len(matchesByFile) == 0 {
 		fmt.Println("No window usages found.")
 		return
@@ -311,10 +546,14 @@ func run() {
 	for _, file := range sortedFiles {
 		matches := matchesByFile[file]
 		totalMatches += len(matches)
-		fmt.Printf("\n%s\n", file)
+		fmt.Printf("\n%s\n", scan.Colorize(file, scan.AnsiCyan))
 		for _, match := range matches {
 			fmt.Printf("  Line %d\n", match.Line)
 			for _, line := range match.Snippet {
+				// Only the matched line (marked ">") is highlighted
+				if strings.HasPrefix(line, ">") {
+					line = scan.Highlight(line, windowAccessRegex, scan.AnsiYellow)
+				}
 				fmt.Printf("  %s\n", line)
 			}
 			fmt.Println("")
@@ -324,7 +563,7 @@ func run() {
 	fmt.Printf("Found %d window usages across %d files.\n", totalMatches, len(matchesByFile))
 
 	if len(identifierCounts) > 0 {
-		fmt.Println("\nTop window identifiers:")
+		fmt.Println("\n" + scan.Colorize("Top window identifiers:", scan.AnsiBold))
 
 		// Sort identifiers by count descending
 		type kv struct {
@@ -351,6 +590,37 @@ func run() {
 	}
 }
 
+// printJSON writes every window access as a finding with the severity of
+// its kind, ordered by file then line
+func printJSON(matchesByFile map[string][]WindowUsageMatch, policy scan.SeverityPolicy) {
+	files := make([]string, 0, len(matchesByFile))
+	for file := range matchesByFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	report := jsonReport{Tool: "window", Findings: []jsonFinding{}}
+	for _, file := range files {
+		for _, match := range matchesByFile[file] {
+			report.Findings = append(report.Findings, jsonFinding{
+				Tool:     "window",
+				File:     file,
+				Line:     match.Line,
+				Severity: policy.Severity(kindWindowAccess),
+				Message:  match.Content,
+				Kind:     kindWindowAccess,
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+		os.Exit(exitError)
+	}
+}
+
 func main() {
 	start := time.Now()
 
@@ -358,5 +628,12 @@ func main() {
 	run()
 
 	duration := time.Since(start)
-	fmt.Printf("\nTotal execution time: %v\n", duration)
+	if !quiet {
+		fmt.Fprintf(logOut, "\nTotal execution time: %v\n", duration)
+		if showStats {
+			stats.Elapsed = duration
+			scan.PrintStats(logOut, "window", stats)
+		}
+	}
+	os.Exit(exitCode)
 }
\ No newline at end of file
@@ -0,0 +1,74 @@
+package scan
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// Baseline records the known findings of a scanner for --baseline: for each
+// file, as a slash path relative to the repository root, a number per stable
+// key the scanner supplies (the line text, a rule name, an issue kind). The
+// number is usually how many findings share the key. Line numbers are
+// deliberately not stored so the baseline survives unrelated edits.
+type Baseline map[string]map[string]int
+
+// NewBaseline returns an empty baseline
+func NewBaseline() Baseline {
+	return make(Baseline)
+}
+
+// Add records one finding
+func (b Baseline) Add(file, key string) {
+	b.Set(file, key, b[file][key]+1)
+}
+
+// Set records n for key, for scanners that keep a measurement rather than a
+// count, such as the line count of a file over a limit
+func (b Baseline) Set(file, key string, n int) {
+	if b[file] == nil {
+		b[file] = make(map[string]int)
+	}
+	b[file][key] = n
+}
+
+// Get returns the number recorded for key and whether the file lists it
+func (b Baseline) Get(file, key string) (int, bool) {
+	n, ok := b[file][key]
+	return n, ok
+}
+
+// Take reports whether a finding is covered by the baseline, using up one of
+// its entries so a file that gains findings has the extras reported as new
+func (b Baseline) Take(file, key string) bool {
+	if b[file][key] <= 0 {
+		return false
+	}
+	b[file][key]--
+	return true
+}
+
+// LoadBaseline reads a --baseline file; a missing file is an empty baseline
+func LoadBaseline(path string) (Baseline, error) {
+	baseline := NewBaseline()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return baseline, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+// WriteBaseline saves the baseline as indented JSON
+func WriteBaseline(path string, baseline Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,44 @@
+package scan
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBaselineTake(t *testing.T) {
+	baseline := NewBaseline()
+	baseline.Add("src/a.ts", "console.log(x);")
+	baseline.Add("src/a.ts", "console.log(x);")
+
+	for i, want := range []bool{true, true, false} {
+		if got := baseline.Take("src/a.ts", "console.log(x);"); got != want {
+			t.Errorf("take %d = %v, want %v", i+1, got, want)
+		}
+	}
+	if baseline.Take("src/b.ts", "console.log(x);") {
+		t.Error("take from a file missing from the baseline succeeded")
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	baseline := NewBaseline()
+	baseline.Add("src/a.ts", "no-console")
+	baseline.Set("src/big.ts", "lines", 812)
+	if err := WriteBaseline(path, baseline); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := loaded.Get("src/big.ts", "lines"); !ok || n != 812 {
+		t.Errorf("Get(src/big.ts, lines) = %d, %v; want 812, true", n, ok)
+	}
+
+	missing, err := LoadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || len(missing) != 0 {
+		t.Errorf("LoadBaseline(missing) = %v, %v; want an empty baseline", missing, err)
+	}
+}
@@ -0,0 +1,68 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Values accepted by --color
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// ANSI styles used when color is enabled
+const (
+	AnsiReset  = "\x1b[0m"
+	AnsiBold   = "\x1b[1m"
+	AnsiRed    = "\x1b[31m"
+	AnsiYellow = "\x1b[33m"
+	AnsiCyan   = "\x1b[36m"
+)
+
+// useColor is set from --color through SetColor
+var useColor bool
+
+// ColorEnabled resolves a --color value. always and never are unconditional;
+// auto colors only when out is a terminal and NO_COLOR is unset or empty.
+func ColorEnabled(mode string, out *os.File) (bool, error) {
+	switch mode {
+	case ColorAlways:
+		return true, nil
+	case ColorNever:
+		return false, nil
+	case ColorAuto:
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		info, err := out.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, fmt.Errorf("invalid value for --color: %s (expected auto, always, or never)", mode)
+}
+
+// SetColor turns Colorize and Highlight on or off; scanners enable it only for
+// a text report on stdout
+func SetColor(enabled bool) {
+	useColor = enabled
+}
+
+// Colorize wraps text in an ANSI style when color is enabled
+func Colorize(text, style string) string {
+	if !useColor || text == "" {
+		return text
+	}
+	return style + text + AnsiReset
+}
+
+// Highlight colorizes every match of pattern in text
+func Highlight(text string, pattern *regexp.Regexp, style string) string {
+	if !useColor {
+		return text
+	}
+	return pattern.ReplaceAllStringFunc(text, func(match string) string {
+		return Colorize(match, style)
+	})
+}
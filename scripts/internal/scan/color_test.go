@@ -0,0 +1,45 @@
+package scan
+
+import (
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	// a regular file is never a terminal, so auto stays off
+	out, err := os.CreateTemp(t.TempDir(), "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	for mode, want := range map[string]bool{ColorAlways: true, ColorNever: false, ColorAuto: false} {
+		got, err := ColorEnabled(mode, out)
+		if err != nil || got != want {
+			t.Errorf("ColorEnabled(%q) = %v, %v; want %v", mode, got, err, want)
+		}
+	}
+	if _, err := ColorEnabled("sometimes", out); err == nil {
+		t.Error("ColorEnabled(\"sometimes\") succeeded, want an error")
+	}
+}
+
+func TestColorize(t *testing.T) {
+	defer SetColor(false)
+	pattern := regexp.MustCompile(`log`)
+
+	SetColor(false)
+	if got := Highlight("console.log(x)", pattern, AnsiRed); got != "console.log(x)" {
+		t.Errorf("Highlight without color = %q", got)
+	}
+
+	SetColor(true)
+	if got := Colorize("", AnsiBold); got != "" {
+		t.Errorf("Colorize(\"\") = %q, want empty", got)
+	}
+	if got, want := Highlight("console.log(x)", pattern, AnsiRed), "console."+AnsiRed+"log"+AnsiReset+"(x)"; got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package scan
+
+import "sync"
+
+// Parallel calls fn for every item on a pool of workers goroutines and returns
+// the results in the order of items. Every item is processed; when any call
+// fails, the error of the first failing item in that order is returned alone.
+func Parallel[T, R any](items []T, workers int, fn func(item T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	if workers > len(items) {
+		workers = len(items)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobCh := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobCh {
+				results[index], errs[index] = fn(items[index])
+			}
+		}()
+	}
+
+	for index := range items {
+		jobCh <- index
+	}
+	close(jobCh)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,44 @@
+package scan
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	items := []int{5, 3, 8, 1, 9, 2, 7}
+	for _, workers := range []int{0, 1, 3, 20} {
+		got, err := Parallel(items, workers, func(n int) (string, error) {
+			return fmt.Sprint(n * n), nil
+		})
+		want := []string{"25", "9", "64", "1", "81", "4", "49"}
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Parallel with %d workers = %v, %v; want %v", workers, got, err, want)
+		}
+	}
+
+	got, err := Parallel([]string{}, 4, func(s string) (int, error) { return len(s), nil })
+	if err != nil || len(got) != 0 {
+		t.Errorf("Parallel on no items = %v, %v; want no results", got, err)
+	}
+}
+
+func TestParallelFirstError(t *testing.T) {
+	visited := make([]bool, 6)
+	_, err := Parallel([]int{0, 1, 2, 3, 4, 5}, 3, func(n int) (int, error) {
+		visited[n] = true
+		if n == 2 || n == 4 {
+			return 0, fmt.Errorf("item %d", n)
+		}
+		return n, nil
+	})
+	if err == nil || err.Error() != "item 2" {
+		t.Errorf("Parallel error = %v, want the first failing item, item 2", err)
+	}
+	for n, ok := range visited {
+		if !ok {
+			t.Errorf("item %d was skipped after an error", n)
+		}
+	}
+}
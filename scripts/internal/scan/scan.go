@@ -0,0 +1,10 @@
+// Package scan holds the pieces shared by the repository scanners in
+// scripts/, so that every scanner reads its flags, writes its reports, and
+// keeps its baseline the same way.
+//
+// The scanners stay standalone programs run with `go run scripts/<name>.go`
+// from the repository root, whose go.mod makes this package importable. Each
+// scanner file carries a `//go:build ignore` constraint, since they all
+// declare main in one directory; `go build ./...` therefore only builds this
+// package, and a scanner's tests run with its files named explicitly.
+package scan
@@ -0,0 +1,107 @@
+package scan
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity levels assigned by --severity and gated by --fail-on, lowest first
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// severityRank orders the severity levels for --fail-on
+var severityRank = map[string]int{SeverityInfo: 1, SeverityWarning: 2, SeverityError: 3}
+
+// SeverityPolicy assigns a severity to every finding kind and decides whether
+// the findings fail the run. The flags read the same in every scanner:
+//
+//	--severity=KIND=LEVEL,...  override the default level of a kind
+//	--fail-on=LEVEL[:N]        fail when more than N findings (default 0) are
+//	                           at LEVEL or above
+type SeverityPolicy struct {
+	levels map[string]string
+	// FailOn is the --fail-on level, empty when the gate is off
+	FailOn string
+	// FailMax is the number of findings --fail-on tolerates
+	FailMax int
+}
+
+// NewSeverityPolicy parses --severity and --fail-on on top of defaults, which
+// must list every kind the scanner reports
+func NewSeverityPolicy(severityFlag, failOnFlag string, defaults map[string]string) (SeverityPolicy, error) {
+	policy := SeverityPolicy{levels: make(map[string]string, len(defaults))}
+	for kind, level := range defaults {
+		policy.levels[kind] = level
+	}
+
+	for _, item := range strings.Split(severityFlag, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kind, level, _ := strings.Cut(item, "=")
+		kind = strings.TrimSpace(kind)
+		level = strings.ToLower(strings.TrimSpace(level))
+		if _, ok := defaults[kind]; !ok {
+			kinds := make([]string, 0, len(defaults))
+			for known := range defaults {
+				kinds = append(kinds, known)
+			}
+			sort.Strings(kinds)
+			return policy, fmt.Errorf("unknown kind %q in --severity (expected one of %s)", kind, strings.Join(kinds, ", "))
+		}
+		if severityRank[level] == 0 {
+			return policy, fmt.Errorf("invalid level %q for %s in --severity (expected error, warning, or info)", level, kind)
+		}
+		policy.levels[kind] = level
+	}
+
+	if failOnFlag != "" {
+		level, max, hasMax := strings.Cut(failOnFlag, ":")
+		policy.FailOn = strings.ToLower(strings.TrimSpace(level))
+		if severityRank[policy.FailOn] == 0 {
+			return policy, fmt.Errorf("invalid value for --fail-on: %s (expected error, warning, or info, optionally followed by :N)", failOnFlag)
+		}
+		if hasMax {
+			parsed, err := strconv.Atoi(strings.TrimSpace(max))
+			if err != nil || parsed < 0 {
+				return policy, fmt.Errorf("invalid threshold in --fail-on: %s (expected a non-negative number)", failOnFlag)
+			}
+			policy.FailMax = parsed
+		}
+	}
+	return policy, nil
+}
+
+// Severity returns the level assigned to kind
+func (p SeverityPolicy) Severity(kind string) string {
+	if level, ok := p.levels[kind]; ok {
+		return level
+	}
+	return SeverityWarning
+}
+
+// Levels returns the level of every kind, for reports that list them
+func (p SeverityPolicy) Levels() map[string]string {
+	return p.levels
+}
+
+// Failing counts the findings, given by kind, at or above the --fail-on level
+// and reports whether they exceed its threshold
+func (p SeverityPolicy) Failing(kinds []string) (int, bool) {
+	if p.FailOn == "" {
+		return 0, false
+	}
+	count := 0
+	for _, kind := range kinds {
+		if severityRank[p.Severity(kind)] >= severityRank[p.FailOn] {
+			count++
+		}
+	}
+	return count, count > p.FailMax
+}
@@ -0,0 +1,47 @@
+package scan
+
+import "testing"
+
+func TestSeverityPolicy(t *testing.T) {
+	defaults := map[string]string{"log": SeverityWarning, "error": SeverityError, "debug": SeverityInfo}
+	cases := []struct {
+		severity, failOn string
+		kinds            []string
+		wantCount        int
+		wantFailed       bool
+	}{
+		{"", "", []string{"log", "error"}, 0, false},
+		{"", "warning", []string{"log", "error", "debug"}, 2, true},
+		{"", "error", []string{"log", "debug"}, 0, false},
+		{"log=error", "error", []string{"log", "debug"}, 1, true},
+		{"", "warning:2", []string{"log", "error"}, 2, false},
+		{"debug=warning", "info:2", []string{"log", "error", "debug"}, 3, true},
+	}
+
+	for _, tc := range cases {
+		policy, err := NewSeverityPolicy(tc.severity, tc.failOn, defaults)
+		if err != nil {
+			t.Fatalf("NewSeverityPolicy(%q, %q): %v", tc.severity, tc.failOn, err)
+		}
+		count, failed := policy.Failing(tc.kinds)
+		if count != tc.wantCount || failed != tc.wantFailed {
+			t.Errorf("severity %q, fail-on %q: Failing(%v) = %d, %v; want %d, %v",
+				tc.severity, tc.failOn, tc.kinds, count, failed, tc.wantCount, tc.wantFailed)
+		}
+	}
+}
+
+func TestSeverityPolicyErrors(t *testing.T) {
+	defaults := map[string]string{"hex": SeverityWarning}
+	for _, args := range [][2]string{
+		{"rgb=error", ""},
+		{"hex=fatal", ""},
+		{"", "fatal"},
+		{"", "error:-1"},
+		{"", "error:many"},
+	} {
+		if _, err := NewSeverityPolicy(args[0], args[1], defaults); err == nil {
+			t.Errorf("NewSeverityPolicy(%q, %q) succeeded, want an error", args[0], args[1])
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statsPrefix starts every --stats footer line
+const statsPrefix = "stats: "
+
+// Stats is the --stats footer a scanner prints to stderr, and that ff-scan
+// reads back through ParseStats
+type Stats struct {
+	Files   int
+	Lines   int
+	Matches int
+	Elapsed time.Duration
+}
+
+// PrintStats writes the --stats footer as one line of key=value pairs, so CI
+// logs can be parsed the same way for every scanner, e.g.
+// "stats: tool=console files=346 lines=88164 matches=1154 elapsed=142.49ms"
+func PrintStats(w io.Writer, tool string, stats Stats) {
+	fmt.Fprintf(w, "%stool=%s files=%d lines=%d matches=%d elapsed=%.2fms\n",
+		statsPrefix, tool, stats.Files, stats.Lines, stats.Matches, stats.elapsedMs())
+}
+
+// ParseStats reads a footer line written by PrintStats
+func ParseStats(line string) (Stats, bool) {
+	fields, ok := strings.CutPrefix(line, statsPrefix)
+	if !ok {
+		return Stats{}, false
+	}
+	var stats Stats
+	for _, field := range strings.Fields(fields) {
+		key, value, _ := strings.Cut(field, "=")
+		var err error
+		switch key {
+		case "files":
+			stats.Files, err = strconv.Atoi(value)
+		case "lines":
+			stats.Lines, err = strconv.Atoi(value)
+		case "matches":
+			stats.Matches, err = strconv.Atoi(value)
+		case "elapsed":
+			var ms float64
+			ms, err = strconv.ParseFloat(strings.TrimSuffix(value, "ms"), 64)
+			stats.Elapsed = time.Duration(math.Round(ms * float64(time.Millisecond)))
+		}
+		if err != nil {
+			return Stats{}, false
+		}
+	}
+	return stats, true
+}
+
+// elapsedMs is the elapsed time in milliseconds, to the microsecond
+func (s Stats) elapsedMs() float64 {
+	return float64(s.Elapsed.Microseconds()) / 1000
+}
+
+// MarshalJSON writes the stats with the elapsed time in milliseconds, as in
+// the ff-scan `all` report
+func (s Stats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Files     int     `json:"files"`
+		Lines     int     `json:"lines"`
+		Matches   int     `json:"matches"`
+		ElapsedMs float64 `json:"elapsedMs"`
+	}{s.Files, s.Lines, s.Matches, s.elapsedMs()})
+}
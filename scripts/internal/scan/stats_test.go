@@ -0,0 +1,45 @@
+package scan
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatsRoundTrip(t *testing.T) {
+	want := Stats{Files: 346, Lines: 88164, Matches: 1154, Elapsed: 142490 * time.Microsecond}
+	var buf bytes.Buffer
+	PrintStats(&buf, "console", want)
+
+	line := buf.String()
+	if line != "stats: tool=console files=346 lines=88164 matches=1154 elapsed=142.49ms\n" {
+		t.Fatalf("PrintStats wrote %q", line)
+	}
+	got, ok := ParseStats(strings.TrimSpace(line))
+	if !ok || got != want {
+		t.Fatalf("ParseStats(%q) = %+v, %v; want %+v", line, got, ok, want)
+	}
+}
+
+func TestParseStatsRejects(t *testing.T) {
+	for _, line := range []string{
+		"Scan completed in 73.71ms",
+		"stats: tool=css files=many",
+		"stats: tool=css elapsed=fast",
+	} {
+		if stats, ok := ParseStats(line); ok {
+			t.Errorf("ParseStats(%q) = %+v, want no footer", line, stats)
+		}
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	data, err := Stats{Files: 2, Lines: 30, Matches: 1, Elapsed: 1500 * time.Microsecond}.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"files":2,"lines":30,"matches":1,"elapsedMs":1.5}`; got != want {
+		t.Errorf("MarshalJSON = %s, want %s", got, want)
+	}
+}
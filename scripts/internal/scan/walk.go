@@ -0,0 +1,194 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultExcludes are the directories no scanner descends into: dependencies,
+// build output, editor state, and vendored browser libraries. --exclude adds
+// to them.
+var DefaultExcludes = []string{
+	"node_modules", ".git", "dist", "build", "out", "coverage",
+	".next", ".turbo", ".idea", ".vscode", "lib", "debug_logs",
+}
+
+// Excludes is a parsed --exclude list. A bare name such as fixtures skips
+// every directory with that name; a value with `*`, `?`, or `/` is a glob over
+// slash paths relative to the working directory, and skips the files and
+// directories it matches.
+type Excludes struct {
+	names map[string]bool
+	globs []*regexp.Regexp
+}
+
+// NewExcludes parses --exclude values on top of DefaultExcludes
+func NewExcludes(values []string) Excludes {
+	excludes := Excludes{names: make(map[string]bool)}
+	for _, name := range DefaultExcludes {
+		excludes.names[name] = true
+	}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		switch {
+		case value == "":
+		case strings.ContainsAny(value, "*?/"):
+			excludes.globs = append(excludes.globs, GlobToRegexp(strings.TrimSuffix(value, "/")))
+		default:
+			excludes.names[value] = true
+		}
+	}
+	return excludes
+}
+
+// Match reports whether a slash path relative to the working directory is
+// excluded, either itself or through one of its directories
+func (e Excludes) Match(relPath string) bool {
+	segments := strings.Split(relPath, "/")
+	for i, segment := range segments {
+		if i < len(segments)-1 && e.names[segment] {
+			return true
+		}
+		if e.matchGlob(strings.Join(segments[:i+1], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Excludes) matchGlob(relPath string) bool {
+	for _, glob := range e.globs {
+		if glob.MatchString(relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkSourceFiles walks roots for files with one of extensions (any file when
+// extensions is empty), skipping excluded directories and files and, with
+// useGitignore, files ignored by git. A root that is a file is kept when its
+// extension matches; a root is walked even when its own name is excluded.
+// Files found under more than one root are listed once, in walk order.
+func WalkSourceFiles(roots []string, extensions map[string]bool, excludes Excludes, useGitignore bool) ([]string, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	relPath := func(path string) string {
+		rel, err := filepath.Rel(workDir, path)
+		if err != nil {
+			return filepath.ToSlash(path)
+		}
+		return filepath.ToSlash(rel)
+	}
+	wanted := func(path string) bool {
+		return len(extensions) == 0 || extensions[strings.ToLower(filepath.Ext(path))]
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (excludes.names[d.Name()] || excludes.matchGlob(relPath(path))) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !wanted(path) || seen[path] || excludes.matchGlob(relPath(path)) {
+				return nil
+			}
+			seen[path] = true
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if useGitignore {
+		return KeepGitFiles(workDir, files)
+	}
+	return files, nil
+}
+
+// KeepGitFiles drops the paths that git ignores (.gitignore,
+// .git/info/exclude, global excludes), keeping tracked files and untracked
+// ones that are not ignored. Relative paths are resolved against dir, the
+// directory git runs in.
+func KeepGitFiles(dir string, paths []string) ([]string, error) {
+	cmd := exec.Command("git", "ls-files", "--cached", "--others", "--exclude-standard")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git ls-files: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	gitFiles := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			gitFiles[filepath.Join(absDir, line)] = true
+		}
+	}
+
+	kept := make([]string, 0, len(paths))
+	for _, path := range paths {
+		abs := path
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(absDir, path)
+		}
+		if gitFiles[filepath.Clean(abs)] {
+			kept = append(kept, path)
+		}
+	}
+	return kept, nil
+}
+
+// GlobToRegexp converts a glob into an anchored regexp over slash paths:
+// `*` and `?` stay within a path segment, `**` spans segments
+func GlobToRegexp(glob string) *regexp.Regexp {
+	glob = filepath.ToSlash(glob)
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i++
+			if i+1 < len(glob) && glob[i+1] == '/' {
+				i++
+				b.WriteString("(?:.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
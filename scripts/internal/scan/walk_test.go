@@ -0,0 +1,123 @@
+package scan
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// chdirTemp builds a tree of empty files in a temporary directory and makes
+// it the working directory for the rest of the test
+func chdirTemp(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(previous) })
+	return dir
+}
+
+func TestWalkSourceFiles(t *testing.T) {
+	chdirTemp(t,
+		"src/a.ts", "src/b.tsx", "src/c.css", "src/a.spec.ts",
+		"src/node_modules/dep/index.ts", "src/lib/vendor.js",
+		"src/fixtures/f.ts", "src/gen/api/client.ts",
+		"dist/bundle.ts",
+	)
+	exts := map[string]bool{".ts": true, ".tsx": true}
+
+	cases := []struct {
+		name     string
+		roots    []string
+		exts     map[string]bool
+		excludes []string
+		want     []string
+	}{
+		{"defaults", []string{"src"}, exts, nil,
+			[]string{"src/a.spec.ts", "src/a.ts", "src/b.tsx", "src/fixtures/f.ts", "src/gen/api/client.ts"}},
+		{"bare name and globs", []string{"src"}, exts, []string{"fixtures", "**/*.spec.ts", "src/gen"},
+			[]string{"src/a.ts", "src/b.tsx"}},
+		{"any extension", []string{"src/fixtures", "src/c.css"}, nil, nil,
+			[]string{"src/fixtures/f.ts", "src/c.css"}},
+		{"excluded root", []string{"dist"}, exts, nil, []string{"dist/bundle.ts"}},
+		{"overlapping roots", []string{"src/fixtures", "src"}, exts, []string{"gen"},
+			[]string{"src/fixtures/f.ts", "src/a.spec.ts", "src/a.ts", "src/b.tsx"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			roots := make([]string, len(tc.roots))
+			for i, root := range tc.roots {
+				roots[i] = filepath.FromSlash(root)
+			}
+			files, err := WalkSourceFiles(roots, tc.exts, NewExcludes(tc.excludes), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(files))
+			for i, file := range files {
+				got[i] = filepath.ToSlash(file)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("WalkSourceFiles = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExcludesMatch(t *testing.T) {
+	excludes := NewExcludes([]string{"fixtures", "docs/assets/", "**/*.gen.ts"})
+	cases := map[string]bool{
+		"src/a.ts":                false,
+		"node_modules/x/index.ts": true,
+		"src/fixtures/f.ts":       true,
+		"src/fixtures.ts":         false,
+		"docs/assets/logo.css":    true,
+		"docs/assets.css":         false,
+		"src/api/client.gen.ts":   true,
+		"src/renderer/lib/rtc.js": true,
+		"src/renderer/library.ts": false,
+	}
+	for path, want := range cases {
+		if got := excludes.Match(path); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestKeepGitFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := chdirTemp(t, ".gitignore", "src/a.ts", "src/generated.ts", "tmp/scratch.ts")
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("generated.ts\ntmp/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	files, err := WalkSourceFiles([]string{"."}, map[string]bool{".ts": true}, NewExcludes(nil), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.FromSlash("src/a.ts")}; !reflect.DeepEqual(files, want) {
+		t.Errorf("WalkSourceFiles with useGitignore = %v, want %v", files, want)
+	}
+}
@@ -1,117 +1,556 @@
+//go:build ignore
+
 package main
 
 import (
 	"bufio"
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Parallel-7/FlashForgeUI-Electron/scripts/internal/scan"
 )
 
-// DisableRule represents a single instance of an eslint-disable directive.
+// DisableRule represents a single instance of a suppression directive.
 type DisableRule struct {
 	File    string
 	Line    int
 	Content string
+	// Kind is the suppression style that matched (see directiveMarkers)
+	Kind string
+	// Type is one of the directive types below
+	Type string
+	// Rules lists the rules named by the directive; empty means all rules
+	Rules []string
+	// Justified is set for eslint directives with a `-- reason` description
+	// or a comment on the following line
+	Justified bool
+	// Snippet holds the surrounding lines when --context is set
+	Snippet []string
+	// Redundant explains why an eslint directive likely has no effect
+	Redundant string
+}
+
+// scanOptions controls what findDisableRules matches and records
+// Shared read-only across workers
+type scanOptions struct {
+	kinds   []string
+	context int
+}
+
+// Directive types accepted by --type
+const (
+	typeFile     = "file"      // eslint-disable block before any code
+	typeBlock    = "block"     // eslint-disable block further down the file
+	typeLine     = "line"      // eslint-disable-line
+	typeNextLine = "next-line" // eslint-disable-next-line
+)
+
+// Suppression kinds accepted by --directives
+const (
+	kindESLint        = "eslint"
+	kindTSIgnore      = "ts-ignore"
+	kindTSExpectError = "ts-expect-error"
+	kindBiome         = "biome-ignore"
+	kindPrettier      = "prettier-ignore"
+)
+
+// directiveMarkers maps each suppression kind to the comment text that introduces it
+var directiveMarkers = map[string]string{
+	kindESLint:        "eslint-disable",
+	kindTSIgnore:      "@ts-ignore",
+	kindTSExpectError: "@ts-expect-error",
+	kindBiome:         "biome-ignore",
+	kindPrettier:      "prettier-ignore",
+}
+
+// Exit codes shared by every scanner
+const (
+	exitFindings = 1 // findings over a --fail-on-* / --max threshold
+	exitError    = 2 // invalid usage or a runtime/I/O error
+)
+
+// Output formats accepted by --format
+const (
+	formatText     = "text"
+	formatMarkdown = "markdown"
+	formatCSV      = "csv"
+	formatJSON     = "json"
+)
+
+// allRulesLabel is reported for directives that don't name any rule
+const allRulesLabel = "(all rules)"
+
+// markerPatterns anchor each marker at the start of a comment's text, so prose
+// that merely mentions a directive doesn't count
+var markerPatterns = func() map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp, len(directiveMarkers))
+	for kind, marker := range directiveMarkers {
+		patterns[kind] = regexp.MustCompile(`^` + regexp.QuoteMeta(marker) + `\b`)
+	}
+	return patterns
+}()
+
+// directivePattern captures the directive suffix and everything after it
+var directivePattern = regexp.MustCompile(`eslint-disable(-next-line|-line)?\b(.*)`)
+
+// enablePattern captures the rule list of an eslint-enable comment
+var enablePattern = regexp.MustCompile(`^eslint-enable\b(.*)`)
+
+// biomePattern captures the biome-ignore variant and the rule categories
+// before its `: reason`
+var biomePattern = regexp.MustCompile(`^biome-ignore(-all|-start|-end)?\b([^:]*)`)
+
+// ruleBudget caps how many directives may suppress a single rule
+type ruleBudget struct {
+	Rule string
+	Max  int
+}
+
+// stringSlice handles comma-separated flags or multiple flag occurrences
+type stringSlice []string
+
+func (s *stringSlice) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSlice) Set(value string) error {
+	parts := strings.Split(value, ",")
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			*s = append(*s, trimmed)
+		}
+	}
+	return nil
 }
 
 // supportedExtensions defines the set of file extensions to scan.
 var supportedExtensions = map[string]bool{
-	".ts":  true,
-	".tsx": true,
-	".js":  true,
-	".jsx": true,
+	".ts":     true,
+	".tsx":    true,
+	".js":     true,
+	".jsx":    true,
+	".vue":    true,
+	".svelte": true,
+}
+
+// componentExtensions are single-file component formats; only their
+// <script> blocks are scanned, template and style sections are skipped
+var componentExtensions = map[string]bool{
+	".vue":    true,
+	".svelte": true,
 }
 
+var (
+	scriptOpenPattern  = regexp.MustCompile(`(?i)<script\b[^>]*>`)
+	scriptClosePattern = regexp.MustCompile(`(?i)</script\s*>`)
+)
+
+// suppressionPattern spans the directive keyword highlighted with --color
+var suppressionPattern = regexp.MustCompile(`(?:eslint-(?:disable|enable)(?:-next-line|-line)?|@ts-ignore|@ts-expect-error|biome-ignore(?:-all|-start|-end)?|prettier-ignore)\b`)
+
 func main() {
 	// 2) Mandatory Execution Timing Feature
 	start := time.Now()
+	exitCode := 0
+	// Keep stdout clean for machine-readable formats
+	var logOut io.Writer = os.Stdout
+	var stats scan.Stats
+	var showStats, quiet bool
 	defer func() {
 		duration := time.Since(start)
-		fmt.Printf("\nTotal execution time: %s\n", duration)
+		if !quiet {
+			fmt.Fprintf(logOut, "\nTotal execution time: %s\n", duration)
+			if showStats {
+				stats.Elapsed = duration
+				scan.PrintStats(logOut, "eslint-disable", stats)
+			}
+		}
+		os.Exit(exitCode)
 	}()
 
 	// 1c) Implement command-line argument parsing
 	// Defaulting to "src" to match the hardcoded logic of the original script,
 	// but allowing override via flags.
-	srcDirPtr := flag.String("dir", "src", "Directory to scan for eslint-disable directives")
+	var dirFlags stringSlice
+	flag.Var(&dirFlags, "dir", "Directory to scan for eslint-disable directives (repeatable, comma-separated, or a glob like packages/*/src; default src)")
+	typePtr := flag.String("type", "", "Only report directives of this type: file, block, line, or next-line")
+	maxPtr := flag.Int("max", -1, "Exit with code 1 when more than N directives are found (-1 disables the check)")
+	failOnMatchPtr := flag.Bool("fail-on-match", false, "Exit with code 1 when any directive is found (same as --max=0)")
+	severityPtr := flag.String("severity", "", "Severity per directive type as type=error|warning|info, e.g. file=error,next-line=info (default warning)")
+	failOnPtr := flag.String("fail-on", "", "Exit with code 1 when more than N directives (default 0) are at LEVEL severity or above, as LEVEL[:N]")
+	var maxRuleFlags stringSlice
+	flag.Var(&maxRuleFlags, "max-rule", "Per-rule budget as rule:N, e.g. no-explicit-any:5 (repeatable or comma-separated)")
+	requireReasonPtr := flag.Bool("require-reason", false, "Report eslint-disable directives without a `-- reason` description or a comment on the next line, and exit 1 if any")
+	warnRedundantPtr := flag.Bool("warn-redundant", false, "List eslint directives that are likely redundant (stacked, duplicated, or inside a disabled block)")
+	byFilePtr := flag.Bool("by-file", false, "Rank files by directive count to find suppression hotspots")
+	byRulePtr := flag.Bool("by-rule", false, "Rank the most-disabled rules with directive and file counts")
+	excludePtr := flag.String("exclude", "", "Comma-separated globs of files to skip, e.g. **/*.spec.ts; a bare name such as fixtures skips directories with that name, in addition to node_modules, dist, and the other defaults")
+	changedPtr := flag.Bool("changed", false, "Only scan files changed on this branch (git diff --name-only <base>...HEAD)")
+	changedBasePtr := flag.String("changed-base", "origin/main", "Base ref used by --changed")
+	stagedPtr := flag.Bool("staged", false, "Only scan files staged for commit (git diff --cached --name-only), e.g. from a pre-commit hook")
+	useGitignorePtr := flag.Bool("use-gitignore", false, "Skip files ignored by git (.gitignore, .git/info/exclude, global excludes)")
+	baselinePtr := flag.String("baseline", "", "Baseline JSON of known directives keyed by file and rule (e.g. eslint-disable-baseline.json); only new ones are reported")
+	updateBaselinePtr := flag.Bool("update-baseline", false, "Rewrite the --baseline file with the current directives and exit")
+	formatPtr := flag.String("format", formatText, "Output format: text, markdown, csv, or json")
+	contextPtr := flag.Int("context", 0, "Print N lines of code around each directive")
+	workersPtr := flag.Int("workers", runtime.NumCPU(), "Number of worker goroutines scanning files in parallel")
+	flag.BoolVar(&showStats, "stats", false, "Print a footer with files scanned, lines inspected, directives found, and elapsed time")
+	flag.BoolVar(&quiet, "quiet", false, "Suppress the timing line and the --stats footer")
+	colorPtr := flag.String("color", scan.ColorAuto, "Color the text report: auto (terminal without NO_COLOR), always, or never")
+	var allowRules stringSlice
+	flag.Var(&allowRules, "allow-rules", "Sanctioned rules left out of the report and budgets (repeatable or comma-separated)")
+	allowFilePtr := flag.String("allow-file", "", "File of sanctioned rules, one per line (# comments allowed)")
+	var directiveFlags stringSlice
+	flag.Var(&directiveFlags, "directives", "Suppression kinds to match: eslint, ts-ignore, ts-expect-error, biome-ignore, prettier-ignore (repeatable or comma-separated; default eslint)")
 	flag.Parse()
 
+	if *failOnMatchPtr && *maxPtr < 0 {
+		*maxPtr = 0
+	}
+	if len(directiveFlags) == 0 {
+		directiveFlags = stringSlice{kindESLint}
+	}
+	var kinds []string
+	seenKinds := make(map[string]bool)
+	for _, kind := range directiveFlags {
+		kind = strings.ToLower(kind)
+		if _, ok := directiveMarkers[kind]; !ok {
+			fmt.Fprintf(os.Stderr, "Invalid --directives value %q (expected eslint, ts-ignore, ts-expect-error, biome-ignore, or prettier-ignore)\n", kind)
+			os.Exit(exitError)
+		}
+		if !seenKinds[kind] {
+			seenKinds[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+
+	ruleBudgets, err := parseRuleBudgets(maxRuleFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --max-rule value: %v\n", err)
+		os.Exit(exitError)
+	}
+
+	policy, err := scan.NewSeverityPolicy(*severityPtr, *failOnPtr, map[string]string{
+		typeFile:     scan.SeverityWarning,
+		typeBlock:    scan.SeverityWarning,
+		typeLine:     scan.SeverityWarning,
+		typeNextLine: scan.SeverityWarning,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+
+	if *allowFilePtr != "" {
+		fileRules, err := loadAllowFile(*allowFilePtr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading allow file: %v\n", err)
+			os.Exit(exitError)
+		}
+		allowRules = append(allowRules, fileRules...)
+	}
+
+	format := strings.ToLower(strings.TrimSpace(*formatPtr))
+	if format != formatText && format != formatMarkdown && format != formatCSV && format != formatJSON {
+		fmt.Fprintf(os.Stderr, "Invalid --format value %q (expected text, markdown, csv, or json)\n", *formatPtr)
+		os.Exit(exitError)
+	}
+	if format != formatText {
+		logOut = os.Stderr
+	}
+
+	enabled, err := scan.ColorEnabled(*colorPtr, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitError)
+	}
+	scan.SetColor(enabled && format == formatText)
+
+	typeFilter := strings.ToLower(strings.TrimSpace(*typePtr))
+	switch typeFilter {
+	case "", typeFile, typeBlock, typeLine, typeNextLine:
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid --type value %q (expected file, block, line, or next-line)\n", *typePtr)
+		os.Exit(exitError)
+	}
+
 	projectRoot, err := os.Getwd()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting current working directory: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+
+	var changedFiles map[string]bool
+	if *changedPtr || *stagedPtr {
+		changedFiles, err = gitChangedFiles(projectRoot, *changedBasePtr, *stagedPtr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing changed files: %v\n", err)
+			os.Exit(exitError)
+		}
 	}
 
-	targetDir := filepath.Join(projectRoot, *srcDirPtr)
+	if len(dirFlags) == 0 {
+		dirFlags = stringSlice{"src"}
+	}
 
-	// Check if directory exists
-	info, err := os.Stat(targetDir)
-	if err != nil || !info.IsDir() {
-		fmt.Fprintf(os.Stderr, "Directory not found: %s\n", targetDir)
-		os.Exit(1)
+	var targetDirs []string
+	for _, dir := range dirFlags {
+		pattern := filepath.Join(projectRoot, dir)
+		matches, err := filepath.Glob(pattern)
+		if err != nil || len(matches) == 0 {
+			// Reported below as a missing directory
+			targetDirs = append(targetDirs, pattern)
+			continue
+		}
+		for _, match := range matches {
+			// A glob like packages/* may also match plain files
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				targetDirs = append(targetDirs, match)
+			}
+		}
 	}
 
-	files, err := collectSourceFiles(targetDir)
+	for _, targetDir := range targetDirs {
+		// Check if directory exists
+		info, err := os.Stat(targetDir)
+		if err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "Directory not found: %s\n", targetDir)
+			os.Exit(exitError)
+		}
+	}
+
+	// Overlapping directories must not report a directive twice; the walk
+	// lists each file once
+	excludes := scan.NewExcludes(strings.Split(*excludePtr, ","))
+	walked, err := scan.WalkSourceFiles(targetDirs, supportedExtensions, excludes, *useGitignorePtr)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error collecting files: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+	var files []string
+	for _, f := range walked {
+		relPath, _ := filepath.Rel(projectRoot, f)
+		if changedFiles != nil && !changedFiles[filepath.ToSlash(relPath)] {
+			continue
+		}
+		files = append(files, f)
 	}
 
 	var allEntries []DisableRule
 
-	for _, file := range files {
-		entries, err := findDisableRules(file, projectRoot)
+	workers := *workersPtr
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	opts := scanOptions{kinds: kinds, context: *contextPtr}
+
+	results, err := scanFiles(files, projectRoot, opts, workers)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+		os.Exit(exitError)
+	}
+	// Results come back in file order so the entries stay deterministic
+	for _, res := range results {
+		stats.Lines += res.lines
+		for _, entry := range res.entries {
+			if typeFilter != "" && entry.Type != typeFilter {
+				continue
+			}
+			if entry, ok := applyAllowlist(entry, allowRules); ok {
+				allEntries = append(allEntries, entry)
+			}
+		}
+	}
+
+	sortEntries(allEntries)
+	stats.Files = len(files)
+
+	baselined := 0
+	if *baselinePtr != "" {
+		baselinePath := filepath.Join(projectRoot, *baselinePtr)
+
+		if *updateBaselinePtr {
+			if err := scan.WriteBaseline(baselinePath, baselineFor(allEntries)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing baseline: %v\n", err)
+				os.Exit(exitError)
+			}
+			fmt.Fprintf(logOut, "📝 Baseline %s updated with %d directives.\n", *baselinePtr, len(allEntries))
+			return
+		}
+
+		baseline, err := scan.LoadBaseline(baselinePath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading file %s: %v\n", file, err)
-			continue
+			fmt.Fprintf(os.Stderr, "Error reading baseline: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		newEntries := filterBaseline(allEntries, baseline)
+		baselined = len(allEntries) - len(newEntries)
+		allEntries = newEntries
+	} else if *updateBaselinePtr {
+		fmt.Fprintln(os.Stderr, "--update-baseline requires --baseline=FILE")
+		os.Exit(exitError)
+	}
+	stats.Matches = len(allEntries)
+
+	switch format {
+	case formatMarkdown:
+		printMarkdown(allEntries, kinds)
+	case formatCSV:
+		if err := printCSV(allEntries); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing CSV: %v\n", err)
+			os.Exit(exitError)
+		}
+	case formatJSON:
+		if err := printJSON(allEntries, policy); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+			os.Exit(exitError)
 		}
-		allEntries = append(allEntries, entries...)
 	}
 
 	if len(allEntries) == 0 {
-		fmt.Println("No eslint-disable rules found!")
+		fmt.Fprintf(logOut, "No %s directives found!\n", strings.Join(kinds, "/"))
+		if baselined > 0 {
+			fmt.Fprintf(logOut, "Baseline: %d known directives suppressed\n", baselined)
+		}
 		return
 	}
 
-	fmt.Println("Found eslint-disable rules:")
-	printTable(allEntries)
+	if format == formatText {
+		fmt.Println(scan.Colorize(fmt.Sprintf("Found %s directives:", strings.Join(kinds, "/")), scan.AnsiBold))
+		printTable(allEntries)
+		if opts.context > 0 {
+			printSnippets(allEntries, opts.context)
+		}
+	}
 
 	uniqueFiles := make(map[string]bool)
 	for _, entry := range allEntries {
 		uniqueFiles[entry.File] = true
 	}
-	fmt.Printf("Total: %d rules in %d files\n", len(allEntries), len(uniqueFiles))
-}
+	fmt.Fprintf(logOut, "Total: %d rules in %d files\n", len(allEntries), len(uniqueFiles))
+	if baselined > 0 {
+		fmt.Fprintf(logOut, "Baseline: %d known directives suppressed\n", baselined)
+	}
 
-// collectSourceFiles walks the directory tree and returns a list of matching file paths.
-func collectSourceFiles(dir string) ([]string, error) {
-	var files []string
+	if format == formatText {
+		if *byRulePtr {
+			printRuleSummary(allEntries)
+		}
+		if *byFilePtr {
+			printFileSummary(allEntries)
+		}
+	}
 
-	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
+	if *warnRedundantPtr {
+		var redundant []DisableRule
+		for _, entry := range allEntries {
+			if entry.Redundant != "" {
+				redundant = append(redundant, entry)
+			}
 		}
-		if !d.IsDir() {
-			ext := filepath.Ext(d.Name())
-			if supportedExtensions[ext] {
-				files = append(files, path)
+		if len(redundant) > 0 {
+			if format == formatText {
+				fmt.Println("\n" + scan.Colorize("Likely redundant directives:", scan.AnsiBold))
+				printRedundant(redundant)
 			}
+			fmt.Fprintf(os.Stderr, "⚠️  %d eslint directives look redundant\n", len(redundant))
 		}
-		return nil
+	}
+
+	if !checkBudgets(allEntries, *maxPtr, ruleBudgets) {
+		exitCode = exitFindings
+	}
+
+	entryTypes := make([]string, len(allEntries))
+	for i, entry := range allEntries {
+		entryTypes[i] = entry.Type
+	}
+	if count, failed := policy.Failing(entryTypes); failed {
+		fmt.Fprintf(os.Stderr, "❌ %d directives at %s severity or above exceeds the --fail-on maximum of %d\n", count, policy.FailOn, policy.FailMax)
+		exitCode = exitFindings
+	}
+
+	if *requireReasonPtr {
+		var unjustified []DisableRule
+		for _, entry := range allEntries {
+			if entry.Kind == kindESLint && !entry.Justified {
+				unjustified = append(unjustified, entry)
+			}
+		}
+		if len(unjustified) > 0 {
+			if format == formatText {
+				fmt.Println("\n" + scan.Colorize("Unjustified disables:", scan.AnsiBold))
+				printTable(unjustified)
+			}
+			fmt.Fprintf(os.Stderr, "❌ %d eslint-disable directives have no justification\n", len(unjustified))
+			exitCode = exitFindings
+		}
+	}
+}
+
+// scanResult is one worker's output for one file
+type scanResult struct {
+	entries []DisableRule
+	lines   int
+}
+
+// scanFiles runs findDisableRules over files with a bounded worker pool and
+// returns the results indexed like files. A file that cannot be read fails
+// the whole scan; the first such error in file order is returned.
+func scanFiles(files []string, projectRoot string, opts scanOptions, workerCount int) ([]scanResult, error) {
+	return scan.Parallel(files, workerCount, func(file string) (scanResult, error) {
+		entries, lines, err := findDisableRules(file, projectRoot, opts)
+		return scanResult{entries: entries, lines: lines}, err
 	})
+}
 
-	return files, err
+// gitChangedFiles returns the supported source files changed between base and
+// HEAD, or with staged the files staged for commit, as paths relative to
+// projectRoot
+func gitChangedFiles(projectRoot, base string, staged bool) (map[string]bool, error) {
+	revision := base + "...HEAD"
+	if staged {
+		revision = "--cached"
+	}
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", revision, "--")
+	cmd.Dir = projectRoot
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git diff %s: %s", revision, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && supportedExtensions[filepath.Ext(line)] {
+			changed[filepath.ToSlash(line)] = true
+		}
+	}
+	return changed, nil
 }
 
-// findDisableRules scans a specific file for eslint-disable directives.
-func findDisableRules(filePath, projectRoot string) ([]DisableRule, error) {
+// findDisableRules scans a specific file for the configured kinds of suppression directives.
+func findDisableRules(filePath, projectRoot string, opts scanOptions) ([]DisableRule, int, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer file.Close()
 
@@ -127,43 +566,857 @@ func findDisableRules(filePath, projectRoot string) ([]DisableRule, error) {
 	// Normalize path separators to forward slashes to match original script's replace(/\\/g, '/')
 	relPath = filepath.ToSlash(relPath)
 
+	// A block directive is file-wide until the first line of code
+	sawCode := false
+	var comments commentScanner
+	// Indexes of eslint directives on the previous line still lacking a reason
+	var awaitingReason []int
+	// Only kept when snippets are requested
+	var lines []string
+	// Rules disabled by an open eslint-disable block, mapped to its line
+	activeBlocks := make(map[string]int)
+	// Index of the previous eslint-disable-next-line match
+	lastNextLine := -1
+	// Component files start outside any <script> block
+	isComponent := componentExtensions[strings.ToLower(filepath.Ext(filePath))]
+	inScript := !isComponent
+
 	for scanner.Scan() {
 		lineNum++
 		text := scanner.Text()
-		if strings.Contains(text, "eslint-disable") {
-			matches = append(matches, DisableRule{
-				File:    relPath,
-				Line:    lineNum,
-				Content: strings.TrimSpace(text),
-			})
+		if opts.context > 0 {
+			lines = append(lines, text)
+		}
+		if isReasonComment(text) {
+			for _, idx := range awaitingReason {
+				matches[idx].Justified = true
+			}
+		}
+		awaitingReason = awaitingReason[:0]
+
+		if isComponent {
+			if !inScript {
+				if scriptOpenPattern.MatchString(text) && !scriptClosePattern.MatchString(text) {
+					inScript = true
+					comments = commentScanner{}
+				}
+				continue
+			}
+			if scriptClosePattern.MatchString(text) {
+				inScript = false
+				continue
+			}
+		}
+
+		code, lineComments := comments.scanLine(text, lineNum)
+		for _, found := range lineComments {
+			comment := strings.TrimSpace(found.text)
+			if match := enablePattern.FindStringSubmatch(comment); match != nil {
+				rules := splitRuleList(match[1])
+				if len(rules) == 0 {
+					activeBlocks = make(map[string]int)
+				}
+				for _, rule := range rules {
+					delete(activeBlocks, rule)
+				}
+				continue
+			}
+			for _, kind := range opts.kinds {
+				if !markerPatterns[kind].MatchString(comment) {
+					continue
+				}
+				entry := DisableRule{
+					File:    relPath,
+					Line:    found.line,
+					Content: strings.TrimSpace(found.source),
+					Kind:    kind,
+					// TypeScript and prettier suppressions apply to the following line
+					Type: typeNextLine,
+				}
+				if kind == kindBiome {
+					var ok bool
+					if entry.Type, entry.Rules, ok = parseBiomeDirective(comment, sawCode); !ok {
+						continue
+					}
+				}
+				if kind == kindESLint {
+					entry.Type = classifyDirective(comment, sawCode)
+					entry.Rules = parseRules(comment)
+					entry.Justified = hasDescription(comment)
+					if !entry.Justified {
+						awaitingReason = append(awaitingReason, len(matches))
+					}
+					entry.Redundant = redundancyReason(entry, activeBlocks)
+
+					if entry.Type == typeNextLine {
+						// A next-line directive followed by another one only covers the comment
+						if lastNextLine != -1 && matches[lastNextLine].Line == entry.Line-1 && matches[lastNextLine].Redundant == "" {
+							matches[lastNextLine].Redundant = fmt.Sprintf("stacked above the directive on line %d; merge them", entry.Line)
+						}
+						lastNextLine = len(matches)
+					}
+					if entry.Type == typeFile || entry.Type == typeBlock {
+						for _, rule := range ruleLabels(entry) {
+							if _, open := activeBlocks[rule]; !open {
+								activeBlocks[rule] = entry.Line
+							}
+						}
+					}
+				}
+				matches = append(matches, entry)
+			}
+		}
+		if !sawCode && isCodeLine(code) {
+			sawCode = true
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if opts.context > 0 {
+		for i := range matches {
+			matches[i].Snippet = createSnippet(lines, matches[i].Line-1, opts.context)
+		}
+	}
+
+	return matches, lineNum, nil
+}
+
+// createSnippet returns the lines around index, numbered, with the directive
+// line marked by `>`
+func createSnippet(lines []string, index int, context int) []string {
+	start := index - context
+	if start < 0 {
+		start = 0
+	}
+	end := index + context
+	if end > len(lines)-1 {
+		end = len(lines) - 1
+	}
+
+	lineNumberWidth := len(strconv.Itoa(end + 1))
+	var snippet []string
+
+	for i := start; i <= end; i++ {
+		prefix := " "
+		if i == index {
+			prefix = ">"
+		}
+		// Format: ">  10 | code"
+		lineNumStr := fmt.Sprintf("%*d", lineNumberWidth, i+1)
+		snippet = append(snippet, fmt.Sprintf("%s %s | %s", prefix, lineNumStr, lines[i]))
+	}
+
+	return snippet
+}
+
+// classifyDirective returns the directive type of an eslint-disable line
+func classifyDirective(text string, sawCode bool) string {
+	match := directivePattern.FindStringSubmatch(text)
+	switch {
+	case match != nil && match[1] == "-next-line":
+		return typeNextLine
+	case match != nil && match[1] == "-line":
+		return typeLine
+	case sawCode:
+		return typeBlock
+	default:
+		return typeFile
+	}
+}
+
+// isCodeLine reports whether the code left on a line once comments are
+// stripped is more than a blank, a shebang, or a 'use strict' directive
+func isCodeLine(code string) bool {
+	trimmed := strings.TrimSpace(code)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#!") {
+		return false
+	}
+	directive := strings.TrimSuffix(trimmed, ";")
+	return directive != "'use strict'" && directive != `"use strict"`
+}
+
+// sourceComment is one comment found by commentScanner
+type sourceComment struct {
+	text string
+	// line and source are the number and text of the line the comment
+	// starts on
+	line   int
+	source string
+}
+
+// commentScanner splits source lines into code and comments, carrying
+// block-comment and template-literal state across lines
+type commentScanner struct {
+	inBlock    bool
+	inTemplate bool
+	// block collects the lines of an unclosed block comment, which is
+	// reported once it closes
+	block []string
+	// blockStart is where the unclosed block comment began
+	blockStart sourceComment
+}
+
+// scanLine returns the line with comments removed and the text of every
+// comment that ends on it; string and template literals are skipped so
+// directive-like text inside them is never reported. A block comment spanning
+// several lines is returned whole, joined with spaces, when it closes, since
+// ESLint matches directives against the entire comment.
+func (cs *commentScanner) scanLine(line string, lineNum int) (string, []sourceComment) {
+	var code strings.Builder
+	var comments []sourceComment
+
+	i := 0
+	for i < len(line) {
+		if cs.inBlock {
+			end := strings.Index(line[i:], "*/")
+			if end == -1 {
+				cs.block = append(cs.block, line[i:])
+				return code.String(), comments
+			}
+			cs.block = append(cs.block, line[i:i+end])
+			comment := cs.blockStart
+			comment.text = strings.Join(cs.block, " ")
+			comments = append(comments, comment)
+			i += end + 2
+			cs.inBlock = false
+			cs.block = nil
+			continue
+		}
+		if cs.inTemplate {
+			end := closingQuote(line, i, '`')
+			code.WriteString(line[i:end])
+			i = end
+			if i < len(line) {
+				code.WriteByte('`')
+				i++
+				cs.inTemplate = false
+			}
+			continue
+		}
+
+		c := line[i]
+		switch {
+		case strings.HasPrefix(line[i:], "//"):
+			comments = append(comments, sourceComment{text: line[i+2:], line: lineNum, source: line})
+			return code.String(), comments
+		case strings.HasPrefix(line[i:], "/*"):
+			end := strings.Index(line[i+2:], "*/")
+			if end == -1 {
+				cs.inBlock = true
+				cs.block = []string{line[i+2:]}
+				cs.blockStart = sourceComment{line: lineNum, source: line}
+				return code.String(), comments
+			}
+			comments = append(comments, sourceComment{text: line[i+2 : i+2+end], line: lineNum, source: line})
+			i += end + 4
+		case (c == '\'' || c == '"' || c == '`') && inJSXText(code.String()):
+			// An apostrophe or backtick in JSX text such as <p>Don't</p>
+			// is plain text, not the start of a literal
+			code.WriteByte(c)
+			i++
+		case c == '\'' || c == '"':
+			// A string cannot span lines, so an unclosed quote is text
+			// rather than a literal swallowing the rest of the line
+			end := closingQuote(line, i+1, c)
+			if end == len(line) {
+				code.WriteByte(c)
+				i++
+				continue
+			}
+			code.WriteString(line[i : end+1])
+			i = end + 1
+		case c == '`':
+			code.WriteByte(c)
+			i++
+			cs.inTemplate = true
+		case c == '/' && regexAllowed(code.String()):
+			end := closingRegex(line, i+1)
+			if end == -1 {
+				code.WriteByte(c)
+				i++
+				continue
+			}
+			code.WriteString(line[i:end])
+			i = end
+		default:
+			code.WriteByte(c)
+			i++
+		}
+	}
+	return code.String(), comments
+}
+
+// regexKeywords are the keywords after which a slash starts a regex literal
+var regexKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true,
+	"case": true, "void": true, "delete": true, "throw": true, "yield": true,
+	"await": true, "else": true, "do": true,
+}
+
+// regexAllowed reports whether a slash following code on the same line is in
+// expression position, where it starts a regex literal rather than dividing:
+// at the start of the line, after an operator or opening bracket, or after a
+// keyword such as return. A slash after < or > is left alone so JSX closing
+// tags such as </p> are not read as regexes.
+func regexAllowed(code string) bool {
+	code = strings.TrimRight(code, " \t")
+	if code == "" {
+		return true
+	}
+	last := code[len(code)-1]
+	if strings.IndexByte("(,=:[!&|?{};+-*%~^", last) >= 0 {
+		return true
+	}
+	start := len(code)
+	for start > 0 && isIdentByte(code[start-1]) {
+		start--
+	}
+	return regexKeywords[code[start:]]
+}
+
+// closingRegex returns the index just past the flags of a regex literal whose
+// body starts at start, or -1 when the line holds no closing slash and so the
+// slash was not a regex after all. A slash inside a [...] class does not
+// close the literal.
+func closingRegex(line string, start int) int {
+	inClass := false
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if inClass {
+				continue
+			}
+			end := i + 1
+			for end < len(line) && isIdentByte(line[end]) {
+				end++
+			}
+			return end
+		}
+	}
+	return -1
+}
+
+// isIdentByte reports whether c can be part of an identifier or regex flag
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// jsxTagPattern finds an opening or closing JSX tag such as <p> or </Trans>
+var jsxTagPattern = regexp.MustCompile(`</?[A-Za-z][\w.:-]*(\s[^<>]*)?>`)
+
+// inJSXText reports whether code on the same line ends inside JSX text: after
+// the > of a tag, with nothing since then that only JavaScript would contain
+func inJSXText(code string) bool {
+	tags := jsxTagPattern.FindAllStringIndex(code, -1)
+	if len(tags) == 0 {
+		return false
+	}
+	end := tags[len(tags)-1][1]
+	return end > 0 && !strings.ContainsAny(code[end:], "{}();=<>")
+}
+
+// closingQuote returns the index of the first unescaped quote at or after
+// start, or len(line) when the literal runs to the end of the line
+func closingQuote(line string, start int, quote byte) int {
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return len(line)
+}
+
+// parseBiomeDirective returns the type and rule categories of a biome-ignore
+// comment; ok is false for biome-ignore-end, which only closes a range
+func parseBiomeDirective(comment string, sawCode bool) (string, []string, bool) {
+	match := biomePattern.FindStringSubmatch(comment)
+	if match == nil || match[1] == "-end" {
+		return "", nil, false
+	}
+
+	directiveType := typeNextLine
+	switch match[1] {
+	case "-all":
+		directiveType = typeFile
+	case "-start":
+		directiveType = typeBlock
+		if !sawCode {
+			directiveType = typeFile
+		}
+	}
+	return directiveType, strings.Fields(match[2]), true
+}
+
+// redundancyReason explains why an eslint directive likely has no effect:
+// it repeats a rule, or every rule it names is already disabled by an open
+// eslint-disable block. It returns "" for directives that look necessary.
+func redundancyReason(entry DisableRule, activeBlocks map[string]int) string {
+	seen := make(map[string]bool)
+	for _, rule := range entry.Rules {
+		if seen[rule] {
+			return fmt.Sprintf("lists %s more than once", rule)
+		}
+		seen[rule] = true
+	}
+
+	if line, ok := activeBlocks[allRulesLabel]; ok {
+		return fmt.Sprintf("inside the eslint-disable block from line %d", line)
+	}
+
+	labels := ruleLabels(entry)
+	blockLine := 0
+	for _, rule := range labels {
+		line, ok := activeBlocks[rule]
+		if !ok {
+			return ""
+		}
+		if line > blockLine {
+			blockLine = line
+		}
+	}
+	return fmt.Sprintf("inside the eslint-disable block from line %d", blockLine)
+}
+
+// hasDescription reports whether an eslint directive carries ESLint's
+// `-- reason` description after its rule list
+func hasDescription(text string) bool {
+	match := directivePattern.FindStringSubmatch(text)
+	if match == nil {
+		return false
+	}
+	rest := match[2]
+	if end := strings.Index(rest, "*/"); end != -1 {
+		rest = rest[:end]
+	}
+	idx := strings.Index(rest, "--")
+	return idx != -1 && strings.TrimSpace(rest[idx+2:]) != ""
+}
+
+// isReasonComment reports whether a line is a comment that can justify the
+// directive above it (another directive does not count)
+func isReasonComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if strings.Contains(trimmed, "eslint-disable") {
+		return false
+	}
+	for _, prefix := range []string{"//", "/*", "*"} {
+		if strings.HasPrefix(trimmed, prefix) && strings.Trim(trimmed, "/* ") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRules extracts the rule list from an eslint-disable directive, e.g.
+// `// eslint-disable-next-line no-console, no-debugger -- reason`
+// yields [no-console no-debugger]
+func parseRules(text string) []string {
+	match := directivePattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil
+	}
+	return splitRuleList(match[2])
+}
+
+// splitRuleList parses the comma-separated rules that follow an eslint
+// directive keyword, ignoring the comment end and any `-- reason`
+func splitRuleList(list string) []string {
+	if end := strings.Index(list, "*/"); end != -1 {
+		list = list[:end]
+	}
+	// ESLint allows a description after `--`
+	if end := strings.Index(list, "--"); end != -1 {
+		list = list[:end]
+	}
+
+	var rules []string
+	for _, part := range strings.Split(list, ",") {
+		if rule := strings.TrimSpace(part); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseRuleBudgets parses --max-rule values of the form rule:N
+func parseRuleBudgets(values []string) ([]ruleBudget, error) {
+	var budgets []ruleBudget
+	for _, value := range values {
+		idx := strings.LastIndex(value, ":")
+		if idx <= 0 {
+			return nil, fmt.Errorf("%q is not in rule:N form", value)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(value[idx+1:]))
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("%q has an invalid limit", value)
+		}
+		budgets = append(budgets, ruleBudget{Rule: strings.TrimSpace(value[:idx]), Max: limit})
+	}
+	return budgets, nil
+}
+
+// ruleMatches reports whether a parsed rule satisfies a budget name; a bare
+// name like no-explicit-any also matches its plugin-scoped form, and
+// ts-ignore matches the @ts-ignore label
+func ruleMatches(rule, name string) bool {
+	return rule == name || rule == "@"+name || strings.HasSuffix(rule, "/"+name)
+}
+
+// ruleLabels returns the names an entry is counted under in rule summaries:
+// its parsed rules, or a label for directives that don't name any
+func ruleLabels(entry DisableRule) []string {
+	if len(entry.Rules) > 0 {
+		return entry.Rules
+	}
+	if entry.Kind == kindESLint {
+		return []string{allRulesLabel}
+	}
+	return []string{directiveMarkers[entry.Kind]}
+}
+
+// checkBudgets prints every exceeded budget and reports whether all were met
+func checkBudgets(entries []DisableRule, maxTotal int, budgets []ruleBudget) bool {
+	ok := true
+	if maxTotal >= 0 && len(entries) > maxTotal {
+		fmt.Fprintf(os.Stderr, "❌ %d suppression directives exceeds the allowed maximum of %d\n", len(entries), maxTotal)
+		ok = false
+	}
+
+	for _, budget := range budgets {
+		count := 0
+		for _, entry := range entries {
+			for _, rule := range ruleLabels(entry) {
+				if ruleMatches(rule, budget.Rule) {
+					count++
+					break
+				}
+			}
+		}
+		if count > budget.Max {
+			fmt.Fprintf(os.Stderr, "❌ %s is disabled %d times, exceeding its budget of %d\n", budget.Rule, count, budget.Max)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// loadAllowFile reads sanctioned rule names, one per line
+func loadAllowFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
-	return matches, nil
+	var rules []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rules = append(rules, line)
+	}
+	return rules, scanner.Err()
 }
 
-// printTable formats and prints the rules in a table.
-func printTable(entries []DisableRule) {
-	// Sort entries: File A-Z, then Line number asc
-	sort.Slice(entries, func(i, j int) bool {
+// applyAllowlist removes sanctioned rules from an entry; ok is false when
+// every rule the entry suppresses is sanctioned and it should be dropped
+func applyAllowlist(entry DisableRule, allowRules []string) (DisableRule, bool) {
+	if len(allowRules) == 0 {
+		return entry, true
+	}
+	isAllowed := func(rule string) bool {
+		for _, allowed := range allowRules {
+			if ruleMatches(rule, allowed) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(entry.Rules) == 0 {
+		return entry, !isAllowed(ruleLabels(entry)[0])
+	}
+
+	var kept []string
+	for _, rule := range entry.Rules {
+		if !isAllowed(rule) {
+			kept = append(kept, rule)
+		}
+	}
+	entry.Rules = kept
+	return entry, len(kept) > 0
+}
+
+// baselineFor records how many directives suppress each rule label per file
+func baselineFor(entries []DisableRule) scan.Baseline {
+	baseline := scan.NewBaseline()
+	for _, entry := range entries {
+		for _, rule := range ruleLabels(entry) {
+			baseline.Add(entry.File, rule)
+		}
+	}
+	return baseline
+}
+
+// filterBaseline drops directives covered by the baseline, using up its
+// entries. Entries must be sorted; when a file gains directives for a rule,
+// the ones furthest down the file are reported as new.
+func filterBaseline(entries []DisableRule, baseline scan.Baseline) []DisableRule {
+	var newEntries []DisableRule
+	for _, entry := range entries {
+		covered := true
+		for _, rule := range ruleLabels(entry) {
+			if !baseline.Take(entry.File, rule) {
+				covered = false
+			}
+		}
+		if !covered {
+			newEntries = append(newEntries, entry)
+		}
+	}
+	return newEntries
+}
+
+// printRuleSummary ranks rules by how often they are suppressed, most
+// frequent first, along with the number of files each appears in
+func printRuleSummary(entries []DisableRule) {
+	ruleCounts := make(map[string]int)
+	ruleFiles := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		for _, rule := range ruleLabels(entry) {
+			ruleCounts[rule]++
+			if ruleFiles[rule] == nil {
+				ruleFiles[rule] = make(map[string]bool)
+			}
+			ruleFiles[rule][entry.File] = true
+		}
+	}
+
+	type ruleCount struct {
+		Rule  string
+		Count int
+		Files int
+	}
+	counts := make([]ruleCount, 0, len(ruleCounts))
+	ruleWidth := len("Rule")
+	for rule, count := range ruleCounts {
+		counts = append(counts, ruleCount{rule, count, len(ruleFiles[rule])})
+		if len(rule) > ruleWidth {
+			ruleWidth = len(rule)
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count == counts[j].Count {
+			return counts[i].Rule < counts[j].Rule
+		}
+		return counts[i].Count > counts[j].Count
+	})
+
+	fmt.Println("\n" + scan.Colorize("Disabled rules:", scan.AnsiBold))
+	fmt.Printf("%-*s  Count  Files\n", ruleWidth, "Rule")
+	fmt.Printf("%s  -----  -----\n", strings.Repeat("-", ruleWidth))
+	for _, rc := range counts {
+		fmt.Printf("%-*s  %5d  %5d\n", ruleWidth, rc.Rule, rc.Count, rc.Files)
+	}
+}
+
+// printSnippets prints the code around each directive
+func printSnippets(entries []DisableRule, context int) {
+	fmt.Println("\n" + scan.Colorize(fmt.Sprintf("Context (±%d lines):", context), scan.AnsiBold))
+	for _, entry := range entries {
+		fmt.Printf("\n%s\n", scan.Colorize(fmt.Sprintf("%s:%d", entry.File, entry.Line), scan.AnsiCyan))
+		for _, line := range entry.Snippet {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	fmt.Println()
+}
+
+// printMarkdown renders the directives as a GitHub-flavored Markdown table
+func printMarkdown(entries []DisableRule, kinds []string) {
+	fmt.Printf("## %s directives (%d)\n", strings.Join(kinds, "/"), len(entries))
+	fmt.Println()
+	if len(entries) == 0 {
+		fmt.Println("✅ No suppression directives found.")
+		return
+	}
+
+	fmt.Println("| File | Line | Kind | Type | Rules | Content |")
+	fmt.Println("| --- | ---: | --- | --- | --- | --- |")
+	for _, entry := range entries {
+		content := "`" + strings.ReplaceAll(escapeMarkdownCell(entry.Content), "`", "'") + "`"
+		fmt.Printf("| `%s` | %d | %s | %s | %s | %s |\n",
+			escapeMarkdownCell(entry.File), entry.Line, entry.Kind, entry.Type,
+			escapeMarkdownCell(strings.Join(ruleLabels(entry), ", ")), content)
+	}
+}
+
+// escapeMarkdownCell keeps pipes from splitting a table cell
+func escapeMarkdownCell(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
+
+// printCSV writes the directives as RFC 4180 CSV with a header row
+func printCSV(entries []DisableRule) error {
+	writer := csv.NewWriter(os.Stdout)
+	if err := writer.Write([]string{"file", "line", "kind", "type", "rules", "content"}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		record := []string{
+			entry.File,
+			strconv.Itoa(entry.Line),
+			entry.Kind,
+			entry.Type,
+			strings.Join(ruleLabels(entry), ", "),
+			entry.Content,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonFinding is one finding in the --format=json report, in the schema
+// shared by every scanner (see scripts/ff-scan.go)
+type jsonFinding struct {
+	Tool     string `json:"tool"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Kind     string `json:"kind"`
+}
+
+// jsonReport is the document written by --format=json
+type jsonReport struct {
+	Tool     string        `json:"tool"`
+	Findings []jsonFinding `json:"findings"`
+}
+
+// printJSON writes each directive as a finding whose kind is the suppression
+// style (eslint, ts-ignore, ...) and whose severity is that of its type
+func printJSON(entries []DisableRule, policy scan.SeverityPolicy) error {
+	report := jsonReport{Tool: "eslint-disable", Findings: make([]jsonFinding, 0, len(entries))}
+	for _, entry := range entries {
+		report.Findings = append(report.Findings, jsonFinding{
+			Tool:     "eslint-disable",
+			File:     entry.File,
+			Line:     entry.Line,
+			Severity: policy.Severity(entry.Type),
+			Message:  entry.Content,
+			Kind:     entry.Kind,
+		})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// sortEntries orders entries by file A-Z, then line number ascending
+// (stable keeps same-line kinds in order)
+func sortEntries(entries []DisableRule) {
+	sort.SliceStable(entries, func(i, j int) bool {
 		if entries[i].File == entries[j].File {
 			return entries[i].Line < entries[j].Line
 		}
 		return entries[i].File < entries[j].File
 	})
+}
+
+// printFileSummary ranks files by directive count, most first, with the number
+// of distinct rules each suppresses
+func printFileSummary(entries []DisableRule) {
+	fileCounts := make(map[string]int)
+	fileRules := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		fileCounts[entry.File]++
+		if fileRules[entry.File] == nil {
+			fileRules[entry.File] = make(map[string]bool)
+		}
+		for _, rule := range ruleLabels(entry) {
+			fileRules[entry.File][rule] = true
+		}
+	}
+
+	type fileCount struct {
+		File  string
+		Count int
+		Rules int
+	}
+	counts := make([]fileCount, 0, len(fileCounts))
+	fileWidth := len("File")
+	for file, count := range fileCounts {
+		counts = append(counts, fileCount{file, count, len(fileRules[file])})
+		if len(file) > fileWidth {
+			fileWidth = len(file)
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count == counts[j].Count {
+			return counts[i].File < counts[j].File
+		}
+		return counts[i].Count > counts[j].Count
+	})
+
+	fmt.Println("\n" + scan.Colorize("Files by directive count:", scan.AnsiBold))
+	fmt.Printf("%-*s  Count  Rules\n", fileWidth, "File")
+	fmt.Printf("%s  -----  -----\n", strings.Repeat("-", fileWidth))
+	for _, fc := range counts {
+		fmt.Printf("%-*s  %5d  %5d\n", fileWidth, fc.File, fc.Count, fc.Rules)
+	}
+}
+
+// printRedundant lists likely-redundant directives with the reason for each
+func printRedundant(entries []DisableRule) {
+	locWidth := len("Location")
+	for _, e := range entries {
+		if loc := fmt.Sprintf("%s:%d", e.File, e.Line); len(loc) > locWidth {
+			locWidth = len(loc)
+		}
+	}
+
+	fmt.Printf("%-*s  Reason\n", locWidth, "Location")
+	fmt.Printf("%s  ------\n", strings.Repeat("-", locWidth))
+	for _, e := range entries {
+		fmt.Printf("%-*s  %s\n", locWidth, fmt.Sprintf("%s:%d", e.File, e.Line), e.Redundant)
+	}
+}
+
+// printTable formats and prints the rules in a table.
+func printTable(entries []DisableRule) {
+	sortEntries(entries)
 
 	// Calculate column widths
 	fileWidth := len("File")
 	lineWidth := len("Line")
+	typeWidth := len(typeNextLine)
+	kindWidth := len("Kind")
 
 	for _, e := range entries {
 		if len(e.File) > fileWidth {
 			fileWidth = len(e.File)
 		}
+		if len(e.Kind) > kindWidth {
+			kindWidth = len(e.Kind)
+		}
 		lineStrLen := len(fmt.Sprintf("%d", e.Line))
 		if lineStrLen > lineWidth {
 			lineWidth = lineStrLen
@@ -172,15 +1425,18 @@ func printTable(entries []DisableRule) {
 
 	// Print Header
 	// Go formatting: %-*s pads to the right (negative width), %*s pads to the left
-	fmt.Printf("%-*s  %*s  Content\n", fileWidth, "File", lineWidth, "Line")
-	fmt.Printf("%s  %s  -------\n", strings.Repeat("-", fileWidth), strings.Repeat("-", lineWidth))
+	fmt.Printf("%-*s  %*s  %-*s  %-*s  Content\n", fileWidth, "File", lineWidth, "Line", kindWidth, "Kind", typeWidth, "Type")
+	fmt.Printf("%s  %s  %s  %s  -------\n", strings.Repeat("-", fileWidth), strings.Repeat("-", lineWidth), strings.Repeat("-", kindWidth), strings.Repeat("-", typeWidth))
 
 	// Print Rows
 	for _, entry := range entries {
-		fmt.Printf("%-*s  %*d  %s\n",
+		fmt.Printf("%-*s  %*d  %-*s  %-*s  %s\n",
 			fileWidth, entry.File,
 			lineWidth, entry.Line,
-			entry.Content,
+			kindWidth, entry.Kind,
+			typeWidth, entry.Type,
+			// Content is the last column, so color cannot break the alignment
+			scan.Highlight(entry.Content, suppressionPattern, scan.AnsiYellow),
 		)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,423 @@
+//go:build ignore
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Run with: go test scripts/scan-eslint-disable.go scripts/scan-eslint-disable_test.go
+
+func TestFindDisableRulesMultiLineBlockComment(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.ts")
+	content := "/*\n  eslint-disable no-console,\n  no-debugger -- legacy logging\n*/\nconsole.log(1);\n/* eslint-enable no-console */\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	matches, _, err := findDisableRules(path, dir, scanOptions{kinds: []string{kindESLint}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1: %+v", len(matches), matches)
+	}
+	got := matches[0]
+	if got.Line != 1 || got.Type != typeFile || !got.Justified {
+		t.Errorf("got line %d, type %s, justified %v; want line 1, type %s, justified", got.Line, got.Type, got.Justified, typeFile)
+	}
+	if want := []string{"no-console", "no-debugger"}; !reflect.DeepEqual(got.Rules, want) {
+		t.Errorf("rules = %v, want %v", got.Rules, want)
+	}
+}
+
+func TestScanFilesFailsOnUnreadableFile(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.ts")
+	if err := os.WriteFile(good, []byte("// eslint-disable-next-line no-console\nconsole.log(1);\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "broken.ts")
+	if err := os.Symlink(filepath.Join(dir, "missing.ts"), broken); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := scanOptions{kinds: []string{kindESLint}}
+	if _, err := scanFiles([]string{good, broken}, dir, opts, 2); err == nil {
+		t.Fatal("scanFiles succeeded on a dangling symlink, want an error")
+	}
+	results, err := scanFiles([]string{good}, dir, opts, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results[0].entries) != 1 {
+		t.Errorf("got %d entries, want 1: %+v", len(results[0].entries), results[0].entries)
+	}
+}
+
+func TestCommentScannerLiterals(t *testing.T) {
+	cases := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "backtick in regex",
+			lines: []string{"const re = /`/;", "// eslint-disable-next-line no-console", "console.log(re);"},
+			want:  []string{"eslint-disable-next-line no-console"},
+		},
+		{
+			name:  "quote in regex",
+			lines: []string{"const re = /'/; // eslint-disable-line no-useless-escape"},
+			want:  []string{"eslint-disable-line no-useless-escape"},
+		},
+		{
+			name:  "quotes and slash in character class",
+			lines: []string{`const re = /["'\x60/]+/gi; // trailing`},
+			want:  []string{"trailing"},
+		},
+		{
+			name:  "slashes in regex are not a comment",
+			lines: []string{`const url = /https?:\/\//.test(s);`},
+		},
+		{
+			name:  "regex after return",
+			lines: []string{"return /'/.test(s); // after return"},
+			want:  []string{"after return"},
+		},
+		{
+			name:  "division is not a regex",
+			lines: []string{"const x = a / b / c; // divided", "const y = (a + b) / 2; // halved"},
+			want:  []string{"divided", "halved"},
+		},
+		{
+			name:  "slashes in string are not a comment",
+			lines: []string{`const url = "http://example.com"; // link`},
+			want:  []string{"link"},
+		},
+		{
+			name:  "apostrophe in JSX text",
+			lines: []string{"<p>Don't panic</p>", "{/* eslint-disable-next-line react/no-danger */}"},
+			want:  []string{"eslint-disable-next-line react/no-danger"},
+		},
+		{
+			name:  "backtick in JSX text",
+			lines: []string{"<kbd>Press ` to open</kbd>", "// eslint-disable-next-line no-console"},
+			want:  []string{"eslint-disable-next-line no-console"},
+		},
+		{
+			name:  "JSX closing tag is not a regex",
+			lines: []string{"<b>bold</b> <i>it</i>; // tags"},
+			want:  []string{"tags"},
+		},
+		{
+			name:  "multi-line template literal hides directives",
+			lines: []string{"const s = `", "// eslint-disable-line no-console", "`; // after"},
+			want:  []string{"after"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cs commentScanner
+			var got []string
+			for i, line := range tc.lines {
+				_, comments := cs.scanLine(line, i+1)
+				for _, c := range comments {
+					got = append(got, strings.TrimSpace(c.text))
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("comments = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassifyDirective(t *testing.T) {
+	cases := []struct {
+		text    string
+		sawCode bool
+		want    string
+	}{
+		{text: "eslint-disable-next-line no-console", want: typeNextLine},
+		{text: "eslint-disable-line no-console", sawCode: true, want: typeLine},
+		{text: "eslint-disable no-console", want: typeFile},
+		{text: "eslint-disable", want: typeFile},
+		{text: "eslint-disable no-console", sawCode: true, want: typeBlock},
+		{text: "eslint-disable-next-line", sawCode: true, want: typeNextLine},
+	}
+
+	for _, tc := range cases {
+		if got := classifyDirective(tc.text, tc.sawCode); got != tc.want {
+			t.Errorf("classifyDirective(%q, %v) = %s, want %s", tc.text, tc.sawCode, got, tc.want)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{text: "eslint-disable-next-line no-console", want: []string{"no-console"}},
+		{text: "eslint-disable-next-line no-console, no-debugger -- legacy", want: []string{"no-console", "no-debugger"}},
+		{text: "eslint-disable-line @typescript-eslint/no-explicit-any,react/no-danger", want: []string{"@typescript-eslint/no-explicit-any", "react/no-danger"}},
+		{text: "eslint-disable no-console */", want: []string{"no-console"}},
+		{text: "eslint-disable no-console*/ const x = 1;", want: []string{"no-console"}},
+		{text: "eslint-disable -- whole file is generated", want: nil},
+		{text: "eslint-disable", want: nil},
+		{text: "eslint-disable-next-line , no-console ,", want: []string{"no-console"}},
+		{text: "prettier-ignore", want: nil},
+	}
+
+	for _, tc := range cases {
+		if got := parseRules(tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseRules(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestSplitRuleList(t *testing.T) {
+	cases := []struct {
+		list string
+		want []string
+	}{
+		{list: "", want: nil},
+		{list: " no-console", want: []string{"no-console"}},
+		{list: " a, b ,c", want: []string{"a", "b", "c"}},
+		{list: " a -- because, b", want: []string{"a"}},
+		{list: " a */", want: []string{"a"}},
+		{list: " -- reason only", want: nil},
+	}
+
+	for _, tc := range cases {
+		if got := splitRuleList(tc.list); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("splitRuleList(%q) = %q, want %q", tc.list, got, tc.want)
+		}
+	}
+}
+
+func TestHasDescription(t *testing.T) {
+	cases := []struct {
+		text string
+		want bool
+	}{
+		{text: "eslint-disable-next-line no-console -- debug output", want: true},
+		{text: "eslint-disable -- generated file", want: true},
+		{text: "eslint-disable-line no-console --", want: false},
+		{text: "eslint-disable-line no-console --   */", want: false},
+		{text: "eslint-disable-line no-console */ // -- not the description", want: false},
+		{text: "eslint-disable-next-line no-console", want: false},
+		{text: "@ts-ignore -- not eslint", want: false},
+	}
+
+	for _, tc := range cases {
+		if got := hasDescription(tc.text); got != tc.want {
+			t.Errorf("hasDescription(%q) = %v, want %v", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestRedundancyReason(t *testing.T) {
+	cases := []struct {
+		name   string
+		rules  []string
+		blocks map[string]int
+		want   string
+	}{
+		{name: "no open blocks", rules: []string{"no-console"}, want: ""},
+		{name: "duplicate rule", rules: []string{"no-console", "no-console"}, want: "lists no-console more than once"},
+		{
+			name:   "covered by an all-rules block",
+			rules:  []string{"no-console"},
+			blocks: map[string]int{allRulesLabel: 2},
+			want:   "inside the eslint-disable block from line 2",
+		},
+		{
+			name:   "every rule covered, latest block reported",
+			rules:  []string{"no-console", "no-debugger"},
+			blocks: map[string]int{"no-console": 3, "no-debugger": 7},
+			want:   "inside the eslint-disable block from line 7",
+		},
+		{
+			name:   "one rule not covered",
+			rules:  []string{"no-console", "no-alert"},
+			blocks: map[string]int{"no-console": 3},
+			want:   "",
+		},
+		{
+			name:   "all-rules directive under a single-rule block",
+			blocks: map[string]int{"no-console": 3},
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := DisableRule{Kind: kindESLint, Rules: tc.rules}
+			blocks := tc.blocks
+			if blocks == nil {
+				blocks = map[string]int{}
+			}
+			if got := redundancyReason(entry, blocks); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseBiomeDirective(t *testing.T) {
+	cases := []struct {
+		comment   string
+		sawCode   bool
+		wantType  string
+		wantRules []string
+		wantOK    bool
+	}{
+		{comment: "biome-ignore lint/style/noVar: legacy", wantType: typeNextLine, wantRules: []string{"lint/style/noVar"}, wantOK: true},
+		{comment: "biome-ignore lint/a lint/b: two", sawCode: true, wantType: typeNextLine, wantRules: []string{"lint/a", "lint/b"}, wantOK: true},
+		{comment: "biome-ignore-all lint: generated", sawCode: true, wantType: typeFile, wantRules: []string{"lint"}, wantOK: true},
+		{comment: "biome-ignore-start format: table", wantType: typeFile, wantRules: []string{"format"}, wantOK: true},
+		{comment: "biome-ignore-start format: table", sawCode: true, wantType: typeBlock, wantRules: []string{"format"}, wantOK: true},
+		{comment: "biome-ignore-end format", wantOK: false},
+		{comment: "eslint-disable no-var", wantOK: false},
+	}
+
+	for _, tc := range cases {
+		gotType, gotRules, gotOK := parseBiomeDirective(tc.comment, tc.sawCode)
+		if gotType != tc.wantType || !reflect.DeepEqual(gotRules, tc.wantRules) || gotOK != tc.wantOK {
+			t.Errorf("parseBiomeDirective(%q, %v) = %s, %q, %v; want %s, %q, %v",
+				tc.comment, tc.sawCode, gotType, gotRules, gotOK, tc.wantType, tc.wantRules, tc.wantOK)
+		}
+	}
+}
+
+// scanSource writes content to a file named name and returns its findings,
+// one "line kind type rules" string each.
+func scanSource(t *testing.T, name, content string, kinds []string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	matches, _, err := findDisableRules(path, dir, scanOptions{kinds: kinds})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range matches {
+		got = append(got, fmt.Sprintf("%d %s %s %s", m.Line, m.Kind, m.Type, strings.Join(m.Rules, ",")))
+	}
+	return got
+}
+
+func TestFindDisableRulesKinds(t *testing.T) {
+	allKinds := []string{kindESLint, kindTSIgnore, kindTSExpectError, kindBiome, kindPrettier}
+	cases := []struct {
+		name    string
+		file    string
+		content string
+		kinds   []string
+		want    []string
+	}{
+		{
+			name:    "ts-ignore and ts-expect-error",
+			file:    "a.ts",
+			content: "const a = 1;\n// @ts-ignore\nfoo();\n/* @ts-expect-error wrong types */\nbar();\n",
+			kinds:   allKinds,
+			want:    []string{"2 ts-ignore next-line ", "4 ts-expect-error next-line "},
+		},
+		{
+			name:    "only the requested kinds",
+			file:    "a.ts",
+			content: "// @ts-ignore\n// eslint-disable-next-line no-console\n// prettier-ignore\n",
+			kinds:   []string{kindPrettier},
+			want:    []string{"3 prettier-ignore next-line "},
+		},
+		{
+			name:    "prettier-ignore in JSX",
+			file:    "a.tsx",
+			content: "const el = (\n  <div>\n    {/* prettier-ignore */}\n    <span   a=\"1\" />\n  </div>\n);\n",
+			kinds:   allKinds,
+			want:    []string{"3 prettier-ignore next-line "},
+		},
+		{
+			name:    "biome ranges",
+			file:    "a.js",
+			content: "// biome-ignore-start format: table\nconst t = [1,2];\n// biome-ignore-end format\n// biome-ignore lint/style/noVar: legacy\nvar x;\n",
+			kinds:   allKinds,
+			want:    []string{"1 biome-ignore file format", "4 biome-ignore next-line lint/style/noVar"},
+		},
+		{
+			name:    "mentions in prose and strings are ignored",
+			file:    "a.ts",
+			content: "// see the @ts-ignore docs\nconst s = '// eslint-disable no-console';\n",
+			kinds:   allKinds,
+			want:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := scanSource(t, tc.file, tc.content, tc.kinds)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFindDisableRulesComponentScripts(t *testing.T) {
+	kinds := []string{kindESLint, kindTSIgnore}
+	cases := []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			name: "vue script block",
+			file: "App.vue",
+			content: "<template>\n  <!-- eslint-disable-next-line vue/no-v-html -->\n  <div>// @ts-ignore</div>\n</template>\n" +
+				"<script setup lang=\"ts\">\n// eslint-disable-next-line no-console\nconsole.log(1);\n</script>\n",
+			want: []string{"6 eslint next-line no-console"},
+		},
+		{
+			name: "svelte with module and instance scripts",
+			file: "Card.svelte",
+			content: "<script context=\"module\">\n// @ts-ignore\nexport const x = y;\n</script>\n" +
+				"<p>// eslint-disable-line no-console</p>\n" +
+				"<SCRIPT>\n/* eslint-disable no-alert */\nalert(1);\n</SCRIPT>\n",
+			want: []string{"2 ts-ignore next-line ", "7 eslint block no-alert"},
+		},
+		{
+			name:    "script without a closing tag runs to the end of the file",
+			file:    "Broken.vue",
+			content: "<script>\nconst a = 1;\n// eslint-disable-next-line no-console\n",
+			want:    []string{"3 eslint next-line no-console"},
+		},
+		{
+			name:    "the same text in a .ts file is scanned throughout",
+			file:    "notes.ts",
+			content: "const a = 1;\n// @ts-ignore\n",
+			want:    []string{"2 ts-ignore next-line "},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := scanSource(t, tc.file, tc.content, kinds)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}